@@ -1,9 +1,10 @@
 package simplejson
 
 import (
+	"bytes"
 	"encoding/json"
-	"errors"
-	"log"
+	"fmt"
+	"strconv"
 )
 
 // returns the current implementation version
@@ -13,6 +14,36 @@ func Version() string {
 
 type Json struct {
 	data interface{}
+
+	// parent and key record where a `Json` returned by Get or GetIndex
+	// lives, so that mutations which replace `data` (such as growing an
+	// array) can be stored back into the containing map or array
+	parent *Json
+	key    interface{}
+
+	// gen counts the times `data` has been swapped for a copy by unshare;
+	// parentGen is the parent's gen when `data` was read from it, and
+	// differs from it if `data` is stale
+	gen       uint64
+	parentGen uint64
+
+	// err is set on a `Json` returned by Get or GetIndex for a value that
+	// does not exist (as opposed to one holding null), recording the first
+	// step of the traversal that failed
+	err error
+
+	// shared is set on the root of a document and holds the maps and
+	// slices it may share with a LazyClone, by address, which must be
+	// copied before they are modified through any `Json` of the document
+	shared map[uintptr]bool
+
+	// order is set on the root of a document created by NewOrderedJson,
+	// and records the key order of its objects
+	order *keyOrder
+
+	// source is set on the root of a document created by
+	// NewPreservingJson, and holds the text it was parsed from
+	source *sourceText
 }
 
 // NewJson returns a pointer to a new `Json` object
@@ -45,45 +76,66 @@ func (j *Json) Encode() ([]byte, error) {
 
 // EncodePretty returns its marshaled data as `[]byte` with indentation
 func (j *Json) EncodePretty() ([]byte, error) {
+	if j.source != nil {
+		return j.source.encode(j.order, j.data)
+	}
+	if o := j.keyOrder(); o != nil {
+		b, err := o.marshal(j.data)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		err = json.Indent(&buf, b, "", "  ")
+		return buf.Bytes(), err
+	}
 	return json.MarshalIndent(&j.data, "", "  ")
 }
 
 // Implements the json.Marshaler interface.
 func (j *Json) MarshalJSON() ([]byte, error) {
+	if j.source != nil {
+		return j.source.encode(j.order, j.data)
+	}
+	if o := j.keyOrder(); o != nil {
+		return o.marshal(j.data)
+	}
 	return json.Marshal(&j.data)
 }
 
 // Set modifies `Json` map by `key` and `value`
 // Useful for changing single key/value in a `Json` object easily.
 func (j *Json) Set(key string, val interface{}) {
+	j.unshare()
 	m, err := j.Map()
 	if err != nil {
 		return
 	}
-	m[key] = val
+	j.keyOrder().set(m, key, val)
 }
 
 // SetPath modifies `Json`, recursively checking/creating map keys for the supplied path,
 // and then finally writing in the value
 func (j *Json) SetPath(branch []string, val interface{}) {
+	j.unshare()
 	if len(branch) == 0 {
-		j.data = val
+		j.update(val)
 		return
 	}
 
 	// in order to insert our branch, we need map[string]interface{}
 	if _, ok := (j.data).(map[string]interface{}); !ok {
 		// have to replace with something suitable
-		j.data = make(map[string]interface{})
+		j.update(make(map[string]interface{}))
 	}
 	curr := j.data.(map[string]interface{})
+	order := j.keyOrder()
 
 	for i := 0; i < len(branch)-1; i++ {
 		b := branch[i]
 		// key exists?
 		if _, ok := curr[b]; !ok {
 			n := make(map[string]interface{})
-			curr[b] = n
+			order.set(curr, b, n)
 			curr = n
 			continue
 		}
@@ -99,16 +151,289 @@ func (j *Json) SetPath(branch []string, val interface{}) {
 	}
 
 	// add remaining k/v
-	curr[branch[len(branch)-1]] = val
+	order.set(curr, branch[len(branch)-1], val)
+}
+
+// SetBranch modifies `Json` by writing `val` at the location described by
+// `branch`, where each segment is either a `string` map key or an `int` array index
+//
+// missing containers are created along the way, as an array when the following
+// segment is an index and as a map otherwise. Arrays are grown as needed, padding
+// with nulls, but an index more than 65536 past the end of its array is rejected.
+// Unlike SetPath, an existing value of the wrong type is never replaced; a
+// `*PointerError` naming the offending segment is returned instead:
+//
+//	err := js.SetBranch([]interface{}{"items", 2, "name"}, "widget")
+func (j *Json) SetBranch(branch []interface{}, val interface{}) error {
+	return j.updateBranch(branch, func(interface{}) (interface{}, error) {
+		return val, nil
+	})
+}
+
+// AppendPath modifies `Json` by appending `vals` to the array found at `branch`,
+// creating the array (and any containers above it) the same way SetBranch does
+//
+//	err := js.AppendPath([]interface{}{"orders", 0, "items"}, item)
+func (j *Json) AppendPath(branch []interface{}, vals ...interface{}) error {
+	return j.updateBranch(branch, func(old interface{}) (interface{}, error) {
+		if old == nil {
+			return append([]interface{}{}, vals...), nil
+		}
+		a, ok := old.([]interface{})
+		if !ok {
+			return nil, errPointerNotArray
+		}
+		return append(a, vals...), nil
+	})
+}
+
+func (j *Json) updateBranch(branch []interface{}, fn func(interface{}) (interface{}, error)) error {
+	j.unshare()
+	data, err := branchUpdate(j.keyOrder(), j.data, branch, 0, fn)
+	if err != nil {
+		err.(*PointerError).Pointer = branchPointer(branch)
+		return err
+	}
+	return j.update(data)
+}
+
+// branchUpdate returns `node` with the value at branch[i:] replaced by the result
+// of `fn`, creating (a null `node` counts as missing) or growing containers as required
+func branchUpdate(order *keyOrder, node interface{}, branch []interface{}, i int, fn func(interface{}) (interface{}, error)) (interface{}, error) {
+	if i == len(branch) {
+		n, err := fn(node)
+		if err != nil {
+			pe := &PointerError{Index: i - 1, Err: err}
+			if i > 0 {
+				pe.Token = fmt.Sprint(branch[i-1])
+			}
+			return nil, pe
+		}
+		return n, nil
+	}
+
+	switch seg := branch[i].(type) {
+	case string:
+		if node == nil {
+			node = make(map[string]interface{})
+		}
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, &PointerError{Token: seg, Index: i, Err: errPointerNotObject}
+		}
+		n, err := branchUpdate(order, m[seg], branch, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		order.set(m, seg, n)
+		return m, nil
+	case int:
+		if seg < 0 {
+			return nil, &PointerError{Token: strconv.Itoa(seg), Index: i, Err: errPointerIndex}
+		}
+		if node == nil {
+			node = []interface{}{}
+		}
+		a, ok := node.([]interface{})
+		if !ok {
+			return nil, &PointerError{Token: strconv.Itoa(seg), Index: i, Err: errPointerNotArray}
+		}
+		if seg-len(a) > maxBranchPadding {
+			return nil, &PointerError{Token: strconv.Itoa(seg), Index: i, Err: errBranchPadding}
+		}
+		for len(a) <= seg {
+			a = append(a, nil)
+		}
+		n, err := branchUpdate(order, a[seg], branch, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		order.release(a[seg], n)
+		a[seg] = n
+		return a, nil
+	}
+	return nil, &PointerError{Token: fmt.Sprint(branch[i]), Index: i, Err: errPointerSegment}
+}
+
+// update replaces the underlying data and stores it back into the
+// map or array this `Json` was obtained from, if any, creating that
+// object (and any missing objects above it) if it does not exist
+//
+// it returns an error if `data` could not be stored, because `Json` was
+// obtained from an array index that is out of range or from a value that
+// is neither an object nor an array
+func (j *Json) update(data interface{}) error {
+	j.data = data
+	if j.parent == nil {
+		j.err = nil
+		return nil
+	}
+	if _, ok := j.key.(string); ok && j.parent.err != nil && j.parent.data == nil {
+		if err := j.parent.update(make(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	switch p := j.parent.data.(type) {
+	case map[string]interface{}:
+		if k, ok := j.key.(string); ok {
+			p[k] = data
+			j.err = nil
+			return nil
+		}
+	case []interface{}:
+		if i, ok := j.key.(int); ok && i >= 0 && i < len(p) {
+			p[i] = data
+			j.err = nil
+			return nil
+		}
+	}
+	if j.err != nil {
+		return j.Err()
+	}
+	return &PathError{Path: j.pointer(), Actual: Missing.String(), Err: ErrNotFound}
+}
+
+// indexError returns the error for an `index` outside the bounds of `j`
+func (j *Json) indexError(index int) error {
+	child := &Json{parent: j, key: index}
+	return &PathError{Path: child.pointer(), Actual: Missing.String(), Err: errPointerOutOfRange}
+}
+
+// SetIndex modifies `Json` array by replacing the element at `index` with `val`
+func (j *Json) SetIndex(index int, val interface{}) error {
+	j.unshare()
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(a) {
+		return j.indexError(index)
+	}
+	j.keyOrder().release(a[index], val)
+	a[index] = val
+	return nil
+}
+
+// Append modifies `Json` array by adding `vals` to its end
+//
+// a null or missing value becomes a new array, and the grown array is stored
+// back into the parent (creating it, and any objects above it, if missing)
+// so that chained calls work as expected:
+//
+//	js.Get("tags").Append("new", "sale")
+func (j *Json) Append(vals ...interface{}) error {
+	j.unshare()
+	if j.data == nil {
+		return j.update(append([]interface{}{}, vals...))
+	}
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	return j.update(append(a, vals...))
+}
+
+// Insert modifies `Json` array by inserting `vals` before `index`,
+// where an `index` equal to the array length appends
+func (j *Json) Insert(index int, vals ...interface{}) error {
+	j.unshare()
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index > len(a) {
+		return j.indexError(index)
+	}
+	n := make([]interface{}, 0, len(a)+len(vals))
+	n = append(n, a[:index]...)
+	n = append(n, vals...)
+	n = append(n, a[index:]...)
+	return j.update(n)
+}
+
+// RemoveIndex modifies `Json` array by removing the element at `index`,
+// shifting any following elements down
+func (j *Json) RemoveIndex(index int) error {
+	j.unshare()
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(a) {
+		return j.indexError(index)
+	}
+	j.keyOrder().forget(a[index])
+	return j.update(append(a[:index:index], a[index+1:]...))
 }
 
 // Del modifies `Json` map by deleting `key` if it is present.
 func (j *Json) Del(key string) {
+	j.unshare()
 	m, err := j.Map()
 	if err != nil {
 		return
 	}
-	delete(m, key)
+	j.keyOrder().del(m, key)
+}
+
+// DelPath modifies `Json` by removing the value at the end of `branch`, where each
+// segment is either a `string` map key or an `int` array index
+//
+// array elements are spliced out, shifting any following elements down. It returns
+// false, leaving `Json` untouched, if nothing exists at `branch`:
+//
+//	js.DelPath("user", "credentials", "token")
+//	js.DelPath("items", 0)
+func (j *Json) DelPath(branch ...interface{}) bool {
+	j.unshare()
+	if len(branch) == 0 {
+		return false
+	}
+	data, ok := branchDel(j.keyOrder(), j.data, branch)
+	if ok {
+		j.update(data)
+	}
+	return ok
+}
+
+func branchDel(order *keyOrder, node interface{}, branch []interface{}) (interface{}, bool) {
+	last := len(branch) == 1
+
+	switch seg := branch[0].(type) {
+	case string:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		child, ok := m[seg]
+		if !ok {
+			return nil, false
+		}
+		if last {
+			order.del(m, seg)
+			return m, true
+		}
+		n, ok := branchDel(order, child, branch[1:])
+		if ok {
+			m[seg] = n
+		}
+		return m, ok
+	case int:
+		a, ok := node.([]interface{})
+		if !ok || seg < 0 || seg >= len(a) {
+			return nil, false
+		}
+		if last {
+			order.forget(a[seg])
+			return append(a[:seg:seg], a[seg+1:]...), true
+		}
+		n, ok := branchDel(order, a[seg], branch[1:])
+		if ok {
+			a[seg] = n
+		}
+		return a, ok
+	}
+	return nil, false
 }
 
 // Get returns a pointer to a new `Json` object
@@ -117,14 +442,23 @@ func (j *Json) Del(key string) {
 // useful for chaining operations (to traverse a nested JSON):
 //
 //	js.Get("top_level").Get("dict").Get("value").Int()
+//
+// if the value does not exist, the returned `Json` remembers why and
+// its accessors (and Err) report it
 func (j *Json) Get(key string) *Json {
+	if j.err != nil {
+		return &Json{parent: j, parentGen: j.gen, key: key, err: j.err}
+	}
 	m, err := j.Map()
-	if err == nil {
-		if val, ok := m[key]; ok {
-			return &Json{val}
-		}
+	if err != nil {
+		return &Json{parent: j, parentGen: j.gen, key: key, err: err}
+	}
+	if val, ok := m[key]; ok {
+		return &Json{data: val, parent: j, parentGen: j.gen, key: key}
 	}
-	return &Json{nil}
+	child := &Json{parent: j, parentGen: j.gen, key: key}
+	child.err = &PathError{Path: child.pointer(), Actual: Missing.String(), Err: ErrNotFound}
+	return child
 }
 
 // GetPath searches for the item as specified by the branch
@@ -147,13 +481,35 @@ func (j *Json) GetPath(branch ...string) *Json {
 //
 //	js.Get("top_level").Get("array").GetIndex(1).Get("key").Int()
 func (j *Json) GetIndex(index int) *Json {
+	if j.err != nil {
+		return &Json{parent: j, parentGen: j.gen, key: index, err: j.err}
+	}
 	a, err := j.Array()
-	if err == nil {
-		if len(a) > index {
-			return &Json{a[index]}
-		}
+	if err != nil {
+		return &Json{parent: j, parentGen: j.gen, key: index, err: err}
 	}
-	return &Json{nil}
+	if index >= 0 && index < len(a) {
+		return &Json{data: a[index], parent: j, parentGen: j.gen, key: index}
+	}
+	child := &Json{parent: j, parentGen: j.gen, key: index}
+	child.err = &PathError{Path: child.pointer(), Actual: Missing.String(), Err: ErrNotFound}
+	return child
+}
+
+// Err returns nil if `Json` holds a value (including null), or a `*PathError`
+// describing why it is missing: the first key or index along the chain of
+// Get, GetIndex and GetPath calls that did not exist, or the value that was
+// not an object or array
+//
+//	port := js.GetPath("server", "listen", "port")
+//	if err := port.Err(); err != nil {
+//		log.Println(err) // json value "/server/listen/port": not found: "/server" does not exist
+//	}
+func (j *Json) Err() error {
+	if j.err == nil {
+		return nil
+	}
+	return j.valueError("", j.err)
 }
 
 // CheckGet returns a pointer to a new `Json` object and
@@ -168,7 +524,7 @@ func (j *Json) CheckGet(key string) (*Json, bool) {
 	m, err := j.Map()
 	if err == nil {
 		if val, ok := m[key]; ok {
-			return &Json{val}, true
+			return &Json{data: val, parent: j, parentGen: j.gen, key: key}, true
 		}
 	}
 	return nil, false
@@ -179,7 +535,7 @@ func (j *Json) Map() (map[string]interface{}, error) {
 	if m, ok := (j.data).(map[string]interface{}); ok {
 		return m, nil
 	}
-	return nil, errors.New("type assertion to map[string]interface{} failed")
+	return nil, j.valueError("object", ErrTypeMismatch)
 }
 
 // Array type asserts to an `array`
@@ -187,7 +543,7 @@ func (j *Json) Array() ([]interface{}, error) {
 	if a, ok := (j.data).([]interface{}); ok {
 		return a, nil
 	}
-	return nil, errors.New("type assertion to []interface{} failed")
+	return nil, j.valueError("array", ErrTypeMismatch)
 }
 
 // Bool type asserts to `bool`
@@ -195,7 +551,7 @@ func (j *Json) Bool() (bool, error) {
 	if s, ok := (j.data).(bool); ok {
 		return s, nil
 	}
-	return false, errors.New("type assertion to bool failed")
+	return false, j.valueError("boolean", ErrTypeMismatch)
 }
 
 // String type asserts to `string`
@@ -203,7 +559,7 @@ func (j *Json) String() (string, error) {
 	if s, ok := (j.data).(string); ok {
 		return s, nil
 	}
-	return "", errors.New("type assertion to string failed")
+	return "", j.valueError("string", ErrTypeMismatch)
 }
 
 // Bytes type asserts to `[]byte`
@@ -211,7 +567,7 @@ func (j *Json) Bytes() ([]byte, error) {
 	if s, ok := (j.data).(string); ok {
 		return []byte(s), nil
 	}
-	return nil, errors.New("type assertion to []byte failed")
+	return nil, j.valueError("string", ErrTypeMismatch)
 }
 
 // StringArray type asserts to an `array` of `string`
@@ -221,20 +577,88 @@ func (j *Json) StringArray() ([]string, error) {
 		return nil, err
 	}
 	retArr := make([]string, 0, len(arr))
-	for _, a := range arr {
+	for i, a := range arr {
 		if a == nil {
 			retArr = append(retArr, "")
 			continue
 		}
 		s, ok := a.(string)
 		if !ok {
-			return nil, errors.New("type assertion to []string failed")
+			return nil, (&Json{data: a, parent: j, parentGen: j.gen, key: i}).valueError("string", ErrTypeMismatch)
 		}
 		retArr = append(retArr, s)
 	}
 	return retArr, nil
 }
 
+// IntArray type asserts to an `array` of `int`
+//
+// elements are coerced like Int but must fit in an `int`; null elements
+// become 0 and the error for any other bad element gives its path
+func (j *Json) IntArray() ([]int, error) {
+	return As[[]int](j)
+}
+
+// Int64Array type asserts to an `array` of `int64`, coercing elements like IntArray
+func (j *Json) Int64Array() ([]int64, error) {
+	return As[[]int64](j)
+}
+
+// Float64Array type asserts to an `array` of `float64`, coercing elements like Float64
+func (j *Json) Float64Array() ([]float64, error) {
+	return As[[]float64](j)
+}
+
+// BoolArray type asserts to an `array` of `bool`, where null elements become false
+func (j *Json) BoolArray() ([]bool, error) {
+	return As[[]bool](j)
+}
+
+// MapArray type asserts to an `array` of `map[string]interface{}`
+//
+// the maps are not copied, so changes to them are reflected in `Json`;
+// null elements become nil maps
+func (j *Json) MapArray() ([]map[string]interface{}, error) {
+	arr, err := j.Array()
+	if err != nil {
+		return nil, err
+	}
+	retArr := make([]map[string]interface{}, 0, len(arr))
+	for i, a := range arr {
+		if a == nil {
+			retArr = append(retArr, nil)
+			continue
+		}
+		m, ok := a.(map[string]interface{})
+		if !ok {
+			return nil, (&Json{data: a, parent: j, parentGen: j.gen, key: i}).valueError("object", ErrTypeMismatch)
+		}
+		retArr = append(retArr, m)
+	}
+	return retArr, nil
+}
+
+// JsonArray returns a `Json` object for each element of an `array`
+//
+// like GetIndex, each element remembers its position so it can be
+// modified in place:
+//
+//	items, _ := js.Get("items").JsonArray()
+//	for _, item := range items {
+//		item.Set("seen", true)
+//	}
+func (j *Json) JsonArray() ([]*Json, error) {
+	arr, err := j.Array()
+	if err != nil {
+		return nil, err
+	}
+	retArr := make([]*Json, 0, len(arr))
+	for i, a := range arr {
+		retArr = append(retArr, &Json{data: a, parent: j, parentGen: j.gen, key: i})
+	}
+	return retArr, nil
+}
+
 // MustArray guarantees the return of a `[]interface{}` (with optional default)
 //
 // useful when you want to interate over array values in a succinct manner:
@@ -243,22 +667,8 @@ func (j *Json) StringArray() ([]string, error) {
 //		fmt.Println(i, v)
 //	}
 func (j *Json) MustArray(args ...[]interface{}) []interface{} {
-	var def []interface{}
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustArray() received too many arguments %d", len(args))
-	}
-
 	a, err := j.Array()
-	if err == nil {
-		return a
-	}
-
-	return def
+	return mustDefault("MustArray", args, a, err)
 }
 
 // MustMap guarantees the return of a `map[string]interface{}` (with optional default)
@@ -268,23 +678,12 @@ func (j *Json) MustArray(args ...[]interface{}) []interface{} {
 //	for k, v := range js.Get("dictionary").MustMap() {
 //		fmt.Println(k, v)
 //	}
+//
+// ranging over a map is unordered even for a document created by
+// NewOrderedJson; use Entries to visit its members in document order.
 func (j *Json) MustMap(args ...map[string]interface{}) map[string]interface{} {
-	var def map[string]interface{}
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustMap() received too many arguments %d", len(args))
-	}
-
 	a, err := j.Map()
-	if err == nil {
-		return a
-	}
-
-	return def
+	return mustDefault("MustMap", args, a, err)
 }
 
 // MustString guarantees the return of a `string` (with optional default)
@@ -293,22 +692,8 @@ func (j *Json) MustMap(args ...map[string]interface{}) map[string]interface{} {
 //
 //	myFunc(js.Get("param1").MustString(), js.Get("optional_param").MustString("my_default"))
 func (j *Json) MustString(args ...string) string {
-	var def string
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustString() received too many arguments %d", len(args))
-	}
-
 	s, err := j.String()
-	if err == nil {
-		return s
-	}
-
-	return def
+	return mustDefault("MustString", args, s, err)
 }
 
 // MustStringArray guarantees the return of a `[]string` (with optional default)
@@ -319,22 +704,56 @@ func (j *Json) MustString(args ...string) string {
 //		fmt.Println(i, s)
 //	}
 func (j *Json) MustStringArray(args ...[]string) []string {
-	var def []string
+	a, err := j.StringArray()
+	return mustDefault("MustStringArray", args, a, err)
+}
 
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustStringArray() received too many arguments %d", len(args))
-	}
+// MustIntArray guarantees the return of a `[]int` (with optional default)
+func (j *Json) MustIntArray(args ...[]int) []int {
+	a, err := j.IntArray()
+	return mustDefault("MustIntArray", args, a, err)
+}
 
-	a, err := j.StringArray()
-	if err == nil {
-		return a
-	}
+// MustInt64Array guarantees the return of a `[]int64` (with optional default)
+func (j *Json) MustInt64Array(args ...[]int64) []int64 {
+	a, err := j.Int64Array()
+	return mustDefault("MustInt64Array", args, a, err)
+}
+
+// MustFloat64Array guarantees the return of a `[]float64` (with optional default)
+func (j *Json) MustFloat64Array(args ...[]float64) []float64 {
+	a, err := j.Float64Array()
+	return mustDefault("MustFloat64Array", args, a, err)
+}
 
-	return def
+// MustBoolArray guarantees the return of a `[]bool` (with optional default)
+func (j *Json) MustBoolArray(args ...[]bool) []bool {
+	a, err := j.BoolArray()
+	return mustDefault("MustBoolArray", args, a, err)
+}
+
+// MustMapArray guarantees the return of a `[]map[string]interface{}` (with optional default)
+//
+// useful when you want to interate over an array of objects in a succinct manner:
+//
+//	for _, m := range js.Get("results").MustMapArray() {
+//		fmt.Println(m["id"])
+//	}
+func (j *Json) MustMapArray(args ...[]map[string]interface{}) []map[string]interface{} {
+	a, err := j.MapArray()
+	return mustDefault("MustMapArray", args, a, err)
+}
+
+// MustJsonArray guarantees the return of a `[]*Json` (with optional default)
+//
+// useful when you want to chain calls on each element of an array:
+//
+//	for _, item := range js.Get("items").MustJsonArray() {
+//		fmt.Println(item.Get("name").MustString())
+//	}
+func (j *Json) MustJsonArray(args ...[]*Json) []*Json {
+	a, err := j.JsonArray()
+	return mustDefault("MustJsonArray", args, a, err)
 }
 
 // MustInt guarantees the return of an `int` (with optional default)
@@ -343,22 +762,8 @@ func (j *Json) MustStringArray(args ...[]string) []string {
 //
 //	myFunc(js.Get("param1").MustInt(), js.Get("optional_param").MustInt(5150))
 func (j *Json) MustInt(args ...int) int {
-	var def int
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustInt() received too many arguments %d", len(args))
-	}
-
 	i, err := j.Int()
-	if err == nil {
-		return i
-	}
-
-	return def
+	return mustDefault("MustInt", args, i, err)
 }
 
 // MustFloat64 guarantees the return of a `float64` (with optional default)
@@ -367,22 +772,8 @@ func (j *Json) MustInt(args ...int) int {
 //
 //	myFunc(js.Get("param1").MustFloat64(), js.Get("optional_param").MustFloat64(5.150))
 func (j *Json) MustFloat64(args ...float64) float64 {
-	var def float64
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustFloat64() received too many arguments %d", len(args))
-	}
-
 	f, err := j.Float64()
-	if err == nil {
-		return f
-	}
-
-	return def
+	return mustDefault("MustFloat64", args, f, err)
 }
 
 // MustBool guarantees the return of a `bool` (with optional default)
@@ -391,22 +782,8 @@ func (j *Json) MustFloat64(args ...float64) float64 {
 //
 //	myFunc(js.Get("param1").MustBool(), js.Get("optional_param").MustBool(true))
 func (j *Json) MustBool(args ...bool) bool {
-	var def bool
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustBool() received too many arguments %d", len(args))
-	}
-
 	b, err := j.Bool()
-	if err == nil {
-		return b
-	}
-
-	return def
+	return mustDefault("MustBool", args, b, err)
 }
 
 // MustInt64 guarantees the return of an `int64` (with optional default)
@@ -415,22 +792,8 @@ func (j *Json) MustBool(args ...bool) bool {
 //
 //	myFunc(js.Get("param1").MustInt64(), js.Get("optional_param").MustInt64(5150))
 func (j *Json) MustInt64(args ...int64) int64 {
-	var def int64
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustInt64() received too many arguments %d", len(args))
-	}
-
 	i, err := j.Int64()
-	if err == nil {
-		return i
-	}
-
-	return def
+	return mustDefault("MustInt64", args, i, err)
 }
 
 // MustUInt64 guarantees the return of an `uint64` (with optional default)
@@ -439,20 +802,6 @@ func (j *Json) MustInt64(args ...int64) int64 {
 //
 //	myFunc(js.Get("param1").MustUint64(), js.Get("optional_param").MustUint64(5150))
 func (j *Json) MustUint64(args ...uint64) uint64 {
-	var def uint64
-
-	switch len(args) {
-	case 0:
-	case 1:
-		def = args[0]
-	default:
-		log.Panicf("MustUint64() received too many arguments %d", len(args))
-	}
-
 	i, err := j.Uint64()
-	if err == nil {
-		return i
-	}
-
-	return def
+	return mustDefault("MustUint64", args, i, err)
 }
@@ -0,0 +1,162 @@
+package simplejson
+
+import (
+	"reflect"
+)
+
+// Clone returns a deep copy of `Json` that shares no maps or slices with it,
+// so that either one can be modified without affecting the other
+//
+//	req := template.Clone()
+//	req.Set("id", id)
+func (j *Json) Clone() *Json {
+	c := &Json{data: deepCopy(j.data), source: j.source}
+	if o := j.keyOrder(); o != nil {
+		c.order = newKeyOrder()
+		c.order.copyTree(o, j.data, c.data)
+	}
+	return c
+}
+
+// LazyClone returns a copy-on-write clone of `Json` that initially shares
+// its data, making it cheap to clone large documents that are mostly read
+//
+// the first modification made through either `Json`, or through a value
+// obtained from it with Get, GetIndex, GetPath or CheckGet, copies the data
+// it would modify. Changes made to maps or slices returned by Interface,
+// Map, Array or their Must variants bypass this tracking and are visible
+// to both documents.
+func (j *Json) LazyClone() *Json {
+	c := &Json{data: j.data, order: j.keyOrder().clone(), source: j.source}
+	if id, ok := containerID(j.data); ok {
+		j.root().markShared(id)
+		c.markShared(id)
+	}
+	return c
+}
+
+// root returns the `Json` at the top of the document `j` belongs to
+func (j *Json) root() *Json {
+	for j.parent != nil {
+		j = j.parent
+	}
+	return j
+}
+
+func (j *Json) markShared(id uintptr) {
+	if j.shared == nil {
+		j.shared = make(map[uintptr]bool)
+	}
+	j.shared[id] = true
+}
+
+// containerID identifies the map or slice held in `v`, if any, by address
+func containerID(v interface{}) (uintptr, bool) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return mapID(x), true
+	case []interface{}:
+		if cap(x) > 0 {
+			return reflect.ValueOf(x).Pointer(), true
+		}
+	}
+	return 0, false
+}
+
+// unshare makes sure that the data about to be modified through `j` is not
+// referenced by a lazy clone, copying it first if necessary. `j` is also
+// re-resolved from its parent if the parent's data has been copied since
+// `j` was obtained, whether through `j` or through another handle.
+//
+// shared maps and slices stay recorded on the root after being copied, so
+// that a `Json` still holding one picks up the copy from its parent
+// instead of making another.
+func (j *Json) unshare() {
+	if j.parent != nil {
+		j.parent.unshare()
+		if j.parentGen != j.parent.gen {
+			j.parentGen = j.parent.gen
+			j.data = j.parent.child(j.key)
+			j.gen++
+		}
+	}
+	shared := j.root().shared
+	for {
+		id, ok := containerID(j.data)
+		if !ok || !shared[id] {
+			return
+		}
+		if j.parent != nil {
+			if cur, ok := containerID(j.parent.child(j.key)); ok && cur != id {
+				j.data = j.parent.child(j.key)
+				j.gen++
+				continue
+			}
+		}
+		data := deepCopy(j.data)
+		j.keyOrder().replace(j.data, data)
+		j.update(data)
+		j.gen++
+	}
+}
+
+// child returns the value stored under `key` in the underlying map
+// or array, or nil if there is none
+func (j *Json) child(key interface{}) interface{} {
+	v, _ := j.lookup(key)
+	return v
+}
+
+// lookup is like child but also reports whether there is such a value
+func (j *Json) lookup(key interface{}) (interface{}, bool) {
+	switch v := j.data.(type) {
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			mv, ok := v[k]
+			return mv, ok
+		}
+	case []interface{}:
+		if i, ok := key.(int); ok && i >= 0 && i < len(v) {
+			return v[i], true
+		}
+	}
+	return nil, false
+}
+
+// refresh re-reads `j` from its parent, in case a value has since been
+// stored at its location through another `Json`
+func (j *Json) refresh() {
+	if j.parent == nil {
+		return
+	}
+	j.parent.refresh()
+	v, ok := j.parent.lookup(j.key)
+	if !ok {
+		return
+	}
+	old, _ := containerID(j.data)
+	if id, _ := containerID(v); id != old {
+		j.gen++
+	}
+	j.data = v
+	j.parentGen = j.parent.gen
+}
+
+// deepCopy returns a copy of `v` that shares no maps or slices with it
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, mv := range x {
+			m[k] = deepCopy(mv)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(x))
+		for i, av := range x {
+			a[i] = deepCopy(av)
+		}
+		return a
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package simplejson
+
+import (
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	js := mustJson(t, `{"a": {"b": [1, 2, {"c": 3}]}, "n": 1.5}`)
+	c := js.Clone()
+
+	c.Get("a").Get("b").GetIndex(2).Set("c", 4)
+	c.Get("a").Get("b").Append(5)
+	c.Set("n", 2)
+
+	if b, _ := js.Encode(); string(b) != `{"a":{"b":[1,2,{"c":3}]},"n":1.5}` {
+		t.Errorf("original modified: %s", b)
+	}
+	if b, _ := c.Encode(); string(b) != `{"a":{"b":[1,2,{"c":4},5]},"n":2}` {
+		t.Errorf("got %s", b)
+	}
+
+	sub := js.Get("a").Clone()
+	sub.Get("b").SetIndex(0, "x")
+	if v := js.GetPath("a", "b").GetIndex(0).MustInt(); v != 1 {
+		t.Errorf("got %#v", v)
+	}
+}
+
+func TestLazyClone(t *testing.T) {
+	js := mustJson(t, `{"a": {"b": [1, 2]}, "c": "d"}`)
+	before := js.Get("a").Get("b")
+
+	c := js.LazyClone()
+	if c.Get("a").Get("b").GetIndex(0).MustInt() != 1 {
+		t.Errorf("clone should share data")
+	}
+
+	c.Get("a").Get("b").Append(3)
+	if a := js.GetPath("a", "b").MustArray(); len(a) != 2 {
+		t.Errorf("original modified through clone: %#v", a)
+	}
+	if a := c.GetPath("a", "b").MustArray(); len(a) != 3 {
+		t.Errorf("got %#v", a)
+	}
+
+	before.SetIndex(0, "x")
+	js.Set("c", "e")
+	c2 := c.LazyClone()
+	c.Del("c")
+
+	expected := map[string]string{
+		"js": `{"a":{"b":["x",2]},"c":"e"}`,
+		"c":  `{"a":{"b":[1,2,3]}}`,
+		"c2": `{"a":{"b":[1,2,3]},"c":"d"}`,
+	}
+	for name, doc := range map[string]*Json{"js": js, "c": c, "c2": c2} {
+		if b, _ := doc.Encode(); string(b) != expected[name] {
+			t.Errorf("%s: got %s expected %s", name, b, expected[name])
+		}
+	}
+}
+
+func TestLazyCloneSiblings(t *testing.T) {
+	js := mustJson(t, `{"a": {"x": 1}, "b": {"y": 1}, "c": [{"z": 1}]}`)
+	c := js.LazyClone()
+	x := js.Get("a")
+	y := js.Get("b")
+	z := js.Get("c").GetIndex(0)
+
+	x.Set("x", 2)
+	y.Set("y", 2)
+	z.Set("z", 2)
+	if b, _ := js.Encode(); string(b) != `{"a":{"x":2},"b":{"y":2},"c":[{"z":2}]}` {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := c.Encode(); string(b) != `{"a":{"x":1},"b":{"y":1},"c":[{"z":1}]}` {
+		t.Errorf("clone modified: %s", b)
+	}
+
+	// handles on the clone are re-resolved the same way
+	c2 := c.LazyClone()
+	cx := c.Get("a")
+	cy := c.Get("b")
+	cx.Set("x", 3)
+	cy.Set("y", 3)
+	if b, _ := c.Encode(); string(b) != `{"a":{"x":3},"b":{"y":3},"c":[{"z":1}]}` {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := c2.Encode(); string(b) != `{"a":{"x":1},"b":{"y":1},"c":[{"z":1}]}` {
+		t.Errorf("clone modified: %s", b)
+	}
+}
+
+func TestLazyCloneSubtree(t *testing.T) {
+	js := mustJson(t, `{"template": {"a": 1}}`)
+	stale := js.Get("template")
+	tmpl := js.Get("template").LazyClone()
+
+	js.Get("template").Set("b", 2)
+	stale.Set("c", 3)
+	if b, _ := tmpl.Encode(); string(b) != `{"a":1}` {
+		t.Errorf("clone modified: %s", b)
+	}
+	if b, _ := js.Encode(); string(b) != `{"template":{"a":1,"b":2,"c":3}}` {
+		t.Errorf("got %s", b)
+	}
+
+	tmpl.Set("d", 4)
+	if b, _ := js.Get("template").Encode(); string(b) != `{"a":1,"b":2,"c":3}` {
+		t.Errorf("original modified: %s", b)
+	}
+}
@@ -0,0 +1,331 @@
+package simplejson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	numberType          = reflect.TypeOf(json.Number(""))
+)
+
+// Decode stores the value of `Json` in the value pointed to by `v`, following
+// the rules of json.Unmarshal but reading the in-memory tree directly instead
+// of encoding and parsing it again
+//
+// struct fields are matched using their `json` tags (including embedded structs
+// and the `string` option), `json.Number` and `interface{}` targets receive
+// numbers unchanged, and types implementing json.Unmarshaler are given the
+// encoded subtree. Errors are a `*PathError` locating the offending value:
+//
+//	var cfg ServerConfig
+//	err := js.GetPath("services", "api").Decode(&cfg)
+func (j *Json) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	if j.err != nil {
+		return j.valueError(expectedKind(rv.Type().Elem()), ErrNotFound)
+	}
+	return decodeValue(j, rv.Elem())
+}
+
+func decodeValue(j *Json, dst reflect.Value) error {
+	v := j.data
+
+	// find the value to decode into, allocating pointers along the way
+	// unless the data is null, in which case the outermost pointer is cleared;
+	// like json.Unmarshal, an interface holding a non-nil pointer is decoded
+	// through that pointer
+	for {
+		if dst.Kind() == reflect.Interface && !dst.IsNil() && v != nil {
+			if e := dst.Elem(); e.Kind() == reflect.Ptr && !e.IsNil() {
+				dst = e
+				continue
+			}
+		}
+		if dst.Kind() != reflect.Ptr && dst.CanAddr() {
+			pv := dst.Addr()
+			if pv.Type().Implements(unmarshalerType) {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return j.valueError(expectedKind(dst.Type()), err)
+				}
+				if err := pv.Interface().(json.Unmarshaler).UnmarshalJSON(b); err != nil {
+					return j.valueError(expectedKind(dst.Type()), err)
+				}
+				return nil
+			}
+			if s, ok := v.(string); ok && pv.Type().Implements(textUnmarshalerType) {
+				if err := pv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+					return j.valueError("string", err)
+				}
+				return nil
+			}
+		}
+		if dst.Kind() != reflect.Ptr {
+			break
+		}
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+
+	t := dst.Type()
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			break
+		}
+		dst.Set(reflect.ValueOf(deepCopy(v)))
+		return nil
+	case reflect.String:
+		if t == numberType {
+			if kindOf(v) != Number {
+				return j.valueError("number", ErrTypeMismatch)
+			}
+			s, err := j.StringLoose()
+			if err != nil {
+				return err
+			}
+			dst.SetString(s)
+			return nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return j.valueError("string", ErrTypeMismatch)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return j.valueError("boolean", ErrTypeMismatch)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := integerLiteral(v); err != nil {
+			return j.valueError("number", err)
+		}
+		i, err := coerceInt64(v, true)
+		if err != nil {
+			return j.valueError("number", err)
+		}
+		if dst.OverflowInt(i) {
+			return j.valueError("number", fmt.Errorf("%w: %d does not fit in %s", ErrOutOfRange, i, t))
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if err := integerLiteral(v); err != nil {
+			return j.valueError("number", err)
+		}
+		u, err := coerceUint64(v, true)
+		if err != nil {
+			return j.valueError("number", err)
+		}
+		if dst.OverflowUint(u) {
+			return j.valueError("number", fmt.Errorf("%w: %d does not fit in %s", ErrOutOfRange, u, t))
+		}
+		dst.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := j.Float64()
+		if err != nil {
+			return err
+		}
+		if dst.OverflowFloat(f) {
+			return j.valueError("number", fmt.Errorf("%w: %g does not fit in %s", ErrOutOfRange, f, t))
+		}
+		dst.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if s, ok := v.(string); ok {
+				b, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return j.valueError("string", err)
+				}
+				dst.SetBytes(b)
+				return nil
+			}
+		}
+		a, ok := v.([]interface{})
+		if !ok {
+			return j.valueError("array", ErrTypeMismatch)
+		}
+		ret := reflect.MakeSlice(t, len(a), len(a))
+		for i, e := range a {
+			if err := decodeValue(&Json{data: e, parent: j, key: i}, ret.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(ret)
+		return nil
+	case reflect.Array:
+		a, ok := v.([]interface{})
+		if !ok {
+			return j.valueError("array", ErrTypeMismatch)
+		}
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(a) {
+				dst.Index(i).Set(reflect.Zero(t.Elem()))
+				continue
+			}
+			if err := decodeValue(&Json{data: a[i], parent: j, key: i}, dst.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return j.valueError("object", ErrTypeMismatch)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(t, len(m)))
+		}
+		for _, k := range sortedKeys(m) {
+			child := &Json{data: m[k], parent: j, key: k}
+			key, err := decodeMapKey(k, t.Key())
+			if err != nil {
+				return child.valueError(expectedKind(t.Elem()), err)
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(child, elem); err != nil {
+				return err
+			}
+			dst.SetMapIndex(key, elem)
+		}
+		return nil
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return j.valueError("object", ErrTypeMismatch)
+		}
+		fields := cachedTypeFields(t)
+		for _, k := range sortedKeys(m) {
+			f := fields.lookup(k)
+			if f == nil {
+				continue
+			}
+			fv, ok := fieldByIndex(dst, f.index)
+			if !ok {
+				continue
+			}
+			child := &Json{data: m[k], parent: j, key: k}
+			if f.quoted {
+				if err := decodeQuoted(child, fv); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := decodeValue(child, fv); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return j.valueError(expectedKind(t), fmt.Errorf("unsupported type %s", t))
+}
+
+// decodeQuoted implements the `string` tag option, where a scalar
+// is encoded as a JSON string holding its JSON representation
+func decodeQuoted(j *Json, dst reflect.Value) error {
+	s, ok := j.data.(string)
+	if !ok {
+		if j.data == nil {
+			return decodeValue(j, dst)
+		}
+		return j.valueError("string", ErrTypeMismatch)
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var inner interface{}
+	if err := dec.Decode(&inner); err != nil {
+		return j.valueError("string", fmt.Errorf("invalid use of string option: %w", err))
+	}
+	return decodeValue(&Json{data: inner, parent: j.parent, key: j.key}, dst)
+}
+
+// decodeMapKey converts an object member name into a map key
+// of type `t`, as json.Unmarshal does
+func decodeMapKey(k string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		key := reflect.New(t)
+		if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k)); err != nil {
+			return reflect.Value{}, err
+		}
+		return key.Elem(), nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(k).Convert(t), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(k, 10, 64)
+		if err != nil || reflect.Zero(t).OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("invalid %s key %q", t, k)
+		}
+		return reflect.ValueOf(i).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || reflect.Zero(t).OverflowUint(u) {
+			return reflect.Value{}, fmt.Errorf("invalid %s key %q", t, k)
+		}
+		return reflect.ValueOf(u).Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported map key type %s", t)
+}
+
+// fieldByIndex returns the (possibly embedded) field at `index`, allocating
+// nil embedded struct pointers; it fails for pointers to unexported structs
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// integerLiteral rejects a decoded number that is not written as a plain
+// integer, e.g. 1.0 or 1e3, as json.Unmarshal does for integer targets
+func integerLiteral(v interface{}) error {
+	n, ok := v.(json.Number)
+	if !ok || !strings.ContainsAny(n.String(), ".eE") {
+		return nil
+	}
+	if _, err := integerDigits(n.String(), false); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s is not an integer", ErrTypeMismatch, n)
+}
@@ -0,0 +1,175 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeBase struct {
+	ID      int    `json:"id"`
+	Created string `json:"created,omitempty"`
+}
+
+// DecodeMeta is exported so that a nil embedded pointer to it can be allocated
+type DecodeMeta struct {
+	Tags []string `json:"tags"`
+}
+
+type upper string
+
+func (u *upper) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*u = upper(strings.ToUpper(s))
+	return nil
+}
+
+type decodeTarget struct {
+	decodeBase
+	*DecodeMeta
+	Name     string            `json:"name"`
+	Port     uint16            `json:"port"`
+	Ratio    float32           `json:"ratio"`
+	Enabled  *bool             `json:"enabled"`
+	Count    int64             `json:"count,string"`
+	Size     json.Number       `json:"size"`
+	Extra    interface{}       `json:"extra"`
+	Labels   map[string]string `json:"labels"`
+	Ports    map[int]bool      `json:"ports"`
+	Pair     [2]int            `json:"pair"`
+	Raw      json.RawMessage   `json:"raw"`
+	Shout    upper             `json:"shout"`
+	When     time.Time         `json:"when"`
+	Data     []byte            `json:"data"`
+	Nested   *decodeBase       `json:"nested"`
+	Skipped  string            `json:"-"`
+	Untagged string
+	Others   map[string]interface{} `json:"others"`
+}
+
+func TestDecode(t *testing.T) {
+	js := mustJson(t, `{
+		"service": {
+			"id": 7,
+			"tags": ["a", "b"],
+			"name": "api",
+			"port": 8080,
+			"ratio": 0.5,
+			"enabled": true,
+			"count": "12",
+			"size": 1e3,
+			"extra": {"x": [1, 2]},
+			"labels": {"env": "prod"},
+			"ports": {"80": true, "443": false},
+			"pair": [1, 2, 3],
+			"raw": {"keep": 1.50},
+			"shout": "hi",
+			"when": "2024-01-02T03:04:05Z",
+			"data": "aGVsbG8=",
+			"nested": null,
+			"Skipped": "x",
+			"untagged": "matched",
+			"others": {"n": 1}
+		}
+	}`)
+
+	var out decodeTarget
+	out.Nested = &decodeBase{ID: 1}
+	if err := js.Get("service").Decode(&out); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	when, _ := time.Parse(time.RFC3339, "2024-01-02T03:04:05Z")
+	enabled := true
+	expected := decodeTarget{
+		decodeBase: decodeBase{ID: 7},
+		DecodeMeta: &DecodeMeta{Tags: []string{"a", "b"}},
+		Name:       "api",
+		Port:       8080,
+		Ratio:      0.5,
+		Enabled:    &enabled,
+		Count:      12,
+		Size:       json.Number("1e3"),
+		Extra:      map[string]interface{}{"x": []interface{}{json.Number("1"), json.Number("2")}},
+		Labels:     map[string]string{"env": "prod"},
+		Ports:      map[int]bool{80: true, 443: false},
+		Pair:       [2]int{1, 2},
+		Raw:        json.RawMessage(`{"keep":1.50}`),
+		Shout:      "HI",
+		When:       when,
+		Data:       []byte("hello"),
+		Untagged:   "matched",
+		Others:     map[string]interface{}{"n": json.Number("1")},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("got %#v", out)
+	}
+
+	// decoded values do not share maps with the tree
+	out.Extra.(map[string]interface{})["x"] = nil
+	if len(js.GetPath("service", "extra", "x").MustArray()) != 2 {
+		t.Errorf("tree was modified")
+	}
+
+	var ints []int
+	if err := js.GetPath("service", "pair").Decode(&ints); err != nil || !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Errorf("got %#v %v", ints, err)
+	}
+	var any interface{}
+	if err := js.GetPath("service", "labels").Decode(&any); err != nil || !reflect.DeepEqual(any, map[string]interface{}{"env": "prod"}) {
+		t.Errorf("got %#v %v", any, err)
+	}
+
+	// an interface holding a pointer is decoded through it
+	labels := map[string]string{}
+	any = &labels
+	if err := js.GetPath("service", "labels").Decode(&any); err != nil || any != &labels || labels["env"] != "prod" {
+		t.Errorf("got %#v %v", any, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	js := mustJson(t, `{
+		"a": {"port": 70000, "ratio": 1.5, "name": 3, "tags": ["x", 1], "count": 12, "big": 1.5e3, "one": 1.0}
+	}`)
+
+	for name, tc := range map[string]struct {
+		v        interface{}
+		path     string
+		sentinel error
+	}{
+		"range":    {new(struct{ Port uint16 }), "/a/port", ErrOutOfRange},
+		"fraction": {new(struct{ Ratio int }), "/a/ratio", ErrFractional},
+		"exponent": {new(struct{ Big int }), "/a/big", ErrTypeMismatch},
+		"decimal":  {new(struct{ One uint }), "/a/one", ErrTypeMismatch},
+		"mismatch": {new(struct{ Name string }), "/a/name", ErrTypeMismatch},
+		"element":  {new(struct{ Tags []string }), "/a/tags/1", ErrTypeMismatch},
+		"quoted": {new(struct {
+			Count int `json:"count,string"`
+		}), "/a/count", ErrTypeMismatch},
+		"container": {new([]string), "/a", ErrTypeMismatch},
+	} {
+		err := js.Get("a").Decode(tc.v)
+		var perr *PathError
+		if !errors.As(err, &perr) || perr.Path != tc.path || !errors.Is(err, tc.sentinel) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+
+	err := js.Get("b").Decode(new(struct{}))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v", err)
+	}
+
+	var s struct{}
+	var invalid *json.InvalidUnmarshalError
+	if err := js.Decode(s); !errors.As(err, &invalid) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChangeType identifies the kind of difference recorded in a Change
+type ChangeType int
+
+const (
+	Added ChangeType = iota
+	Removed
+	Changed
+	TypeChanged
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	case TypeChanged:
+		return "type changed"
+	}
+	return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Change is a single difference between two documents
+type Change struct {
+	Type ChangeType
+	Path string      // RFC 6901 JSON Pointer to the value
+	From interface{} // the old value, nil when Added
+	To   interface{} // the new value, nil when Removed
+}
+
+// Changes is the ordered result of Diff
+type Changes []Change
+
+// Diff walks `a` and `b` and returns every difference between them, visiting
+// object members in key order and array elements by position
+//
+// numbers are compared by value, so `json.Number("1.0")` and `float64(1)` are
+// equal. Removed array elements are reported from the highest index down so
+// that the result converts directly into a valid patch:
+//
+//	for _, c := range simplejson.Diff(before, after) {
+//		log.Printf("%s %s", c.Type, c.Path)
+//	}
+func Diff(a, b *Json) Changes {
+	return diffValues("", a.data, b.data, nil)
+}
+
+func diffValues(path string, a, b interface{}, changes Changes) Changes {
+	ka, kb := kindOf(a), kindOf(b)
+	if ka != kb {
+		return append(changes, Change{TypeChanged, path, a, b})
+	}
+	if ka == Object || ka == Array {
+		// so that containers stored with Set, such as a `[]string`,
+		// are walked like their decoded counterparts
+		a, b = genericValue(a), genericValue(b)
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(x) {
+			if _, ok := y[k]; !ok {
+				changes = append(changes, Change{Removed, path + "/" + escapePointerToken(k), x[k], nil})
+			}
+		}
+		for _, k := range sortedKeys(y) {
+			p := path + "/" + escapePointerToken(k)
+			xv, ok := x[k]
+			if !ok {
+				changes = append(changes, Change{Added, p, nil, y[k]})
+				continue
+			}
+			changes = diffValues(p, xv, y[k], changes)
+		}
+		return changes
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(x) && i < len(y); i++ {
+			changes = diffValues(path+"/"+strconv.Itoa(i), x[i], y[i], changes)
+		}
+		for i := len(x) - 1; i >= len(y); i-- {
+			changes = append(changes, Change{Removed, path + "/" + strconv.Itoa(i), x[i], nil})
+		}
+		for i := len(x); i < len(y); i++ {
+			changes = append(changes, Change{Added, path + "/" + strconv.Itoa(i), nil, y[i]})
+		}
+		return changes
+	}
+
+	if !valuesEqual(a, b) {
+		changes = append(changes, Change{Changed, path, a, b})
+	}
+	return changes
+}
+
+// Patch converts the changes into an RFC 6902 JSON Patch that
+// ApplyPatch can use to turn the first document into the second
+func (c Changes) Patch() *Json {
+	ops := make([]interface{}, 0, len(c))
+	for _, change := range c {
+		op := map[string]interface{}{"path": change.Path}
+		switch change.Type {
+		case Added:
+			op["op"] = "add"
+			op["value"] = deepCopy(change.To)
+		case Removed:
+			op["op"] = "remove"
+		default:
+			op["op"] = "replace"
+			op["value"] = deepCopy(change.To)
+		}
+		ops = append(ops, op)
+	}
+	return &Json{data: ops}
+}
+
+// String renders a human-readable report with one change per line, each
+// prefixed by `+` (added), `-` (removed), `~` (changed) or `!` (type changed)
+// and followed by the path and the JSON encoded values, e.g.
+//
+//	~ /port: 80 -> 8080
+//	! /timeout: "30s" (string) -> 30 (number)
+func (c Changes) String() string {
+	var b strings.Builder
+	for _, change := range c {
+		switch change.Type {
+		case Added:
+			fmt.Fprintf(&b, "+ %s: %s\n", displayPath(change.Path), reportValue(change.To))
+		case Removed:
+			fmt.Fprintf(&b, "- %s: %s\n", displayPath(change.Path), reportValue(change.From))
+		case Changed:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", displayPath(change.Path),
+				reportValue(change.From), reportValue(change.To))
+		case TypeChanged:
+			fmt.Fprintf(&b, "! %s: %s (%s) -> %s (%s)\n", displayPath(change.Path),
+				reportValue(change.From), kindOf(change.From),
+				reportValue(change.To), kindOf(change.To))
+		}
+	}
+	return b.String()
+}
+
+func displayPath(p string) string {
+	if p == "" {
+		return "(root)"
+	}
+	return p
+}
+
+func reportValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,80 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := mustJson(t, `{
+		"name": "svc",
+		"port": 80,
+		"ratio": 1.0,
+		"debug": true,
+		"timeout": "30s",
+		"servers": ["a", "b", "c", "d"],
+		"tags": ["x"],
+		"a/b": {"c": 1}
+	}`)
+	b := mustJson(t, `{
+		"name": "svc",
+		"port": 8080,
+		"timeout": 30,
+		"servers": ["a", "B"],
+		"tags": ["x", "y"],
+		"a/b": {"c": 1, "d": null}
+	}`)
+	b.Set("ratio", float64(1))
+
+	expected := Changes{
+		{Removed, "/debug", true, nil},
+		{Added, "/a~1b/d", nil, nil},
+		{Changed, "/port", json.Number("80"), json.Number("8080")},
+		{Changed, "/servers/1", "b", "B"},
+		{Removed, "/servers/3", "d", nil},
+		{Removed, "/servers/2", "c", nil},
+		{Added, "/tags/1", nil, "y"},
+		{TypeChanged, "/timeout", "30s", json.Number("30")},
+	}
+	changes := Diff(a, b)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("got %#v", changes)
+	}
+
+	report := `- /debug: true
++ /a~1b/d: null
+~ /port: 80 -> 8080
+~ /servers/1: "b" -> "B"
+- /servers/3: "d"
+- /servers/2: "c"
++ /tags/1: "y"
+! /timeout: "30s" (string) -> 30 (number)
+`
+	if s := changes.String(); s != report {
+		t.Errorf("got %s", s)
+	}
+
+	if err := a.ApplyPatch(changes.Patch()); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if d := Diff(a, b); len(d) != 0 {
+		t.Errorf("got %#v", d)
+	}
+
+	if d := Diff(mustJson(t, `[1]`), mustJson(t, `{"a":1}`)); len(d) != 1 || d[0].Type != TypeChanged || d[0].Path != "" {
+		t.Errorf("got %#v", d)
+	}
+
+	// Go values stored with Set compare like their decoded counterparts
+	typed := New()
+	typed.Set("l", []string{"a"})
+	typed.Set("m", map[string]int{"k": 1})
+	if d := Diff(mustJson(t, `{"l":["a"],"m":{"k":1}}`), typed); len(d) != 0 {
+		t.Errorf("got %#v", d)
+	}
+	typed.Set("l", []string{"a", "b"})
+	if d := Diff(mustJson(t, `{"l":["a"],"m":{"k":1}}`), typed); len(d) != 1 || d[0].Type != Added || d[0].Path != "/l/1" {
+		t.Errorf("got %#v", d)
+	}
+}
@@ -0,0 +1,169 @@
+package simplejson
+
+import (
+	"strconv"
+)
+
+// EqualOptions controls how EqualWith compares two documents
+type EqualOptions struct {
+	// IgnoreArrayOrder compares arrays as multisets, so that
+	// [1, 2] and [2, 1] are equal
+	IgnoreArrayOrder bool
+
+	// IgnorePaths lists RFC 6901 JSON Pointers whose values are not
+	// compared, with `*` matching any single key or index, e.g.
+	// "/items/*/updated_at". Below an unordered array, indices refer
+	// to the position in the receiver.
+	IgnorePaths []string
+}
+
+// Equal reports whether `Json` and `other` hold the same JSON value,
+// comparing numbers by value so that `json.Number("10")` from NewJson
+// equals `int(10)` from Set
+func (j *Json) Equal(other *Json) bool {
+	return valuesEqual(j.data, other.data)
+}
+
+// EqualWith is like Equal but allows array order and selected
+// paths to be ignored
+//
+// like regexp.MustCompile, it panics if one of the IgnorePaths is not
+// a valid JSON Pointer, as such paths are normally fixed in the source:
+//
+//	js.EqualWith(other, simplejson.EqualOptions{
+//		IgnoreArrayOrder: true,
+//		IgnorePaths:      []string{"/meta/generated_at"},
+//	})
+func (j *Json) EqualWith(other *Json, opts EqualOptions) bool {
+	e := &equality{unordered: opts.IgnoreArrayOrder}
+	for _, p := range opts.IgnorePaths {
+		tokens, err := parsePointer(p)
+		if err != nil {
+			panic("simplejson: EqualWith: IgnorePaths: " + err.Error())
+		}
+		e.ignore = append(e.ignore, tokens)
+	}
+	return e.equal(nil, j.data, other.data)
+}
+
+// valuesEqual reports whether two decoded JSON values are equal,
+// comparing numbers by value rather than representation
+func valuesEqual(a, b interface{}) bool {
+	return (&equality{}).equal(nil, a, b)
+}
+
+type equality struct {
+	unordered bool
+	ignore    [][]string
+}
+
+func (e *equality) ignored(path []string) bool {
+	if len(e.ignore) == 0 {
+		return false
+	}
+	for _, pattern := range e.ignore {
+		if len(pattern) != len(path) {
+			continue
+		}
+		match := true
+		for i := range pattern {
+			if pattern[i] != "*" && pattern[i] != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// child extends `path` by `tok`; paths are only tracked
+// when there is something to ignore
+func (e *equality) child(path []string, tok string) []string {
+	if len(e.ignore) == 0 {
+		return nil
+	}
+	return append(path[:len(path):len(path)], tok)
+}
+
+func (e *equality) equal(path []string, a, b interface{}) bool {
+	if e.ignored(path) {
+		return true
+	}
+	if c, ok := compareNumbers(a, b); ok {
+		return c == 0
+	}
+	a, b = genericValue(a), genericValue(b)
+
+	switch x := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		if e.unordered {
+			return e.equalUnordered(path, x, y)
+		}
+		for i := range x {
+			if !e.equal(e.child(path, strconv.Itoa(i)), x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, xv := range x {
+			p := e.child(path, k)
+			yv, ok := y[k]
+			if !ok {
+				if e.ignored(p) {
+					continue
+				}
+				return false
+			}
+			if !e.equal(p, xv, yv) {
+				return false
+			}
+		}
+		for k := range y {
+			if _, ok := x[k]; !ok && !e.ignored(e.child(path, k)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// equalUnordered matches every element of `x` with a distinct equal element of `y`
+func (e *equality) equalUnordered(path []string, x, y []interface{}) bool {
+	used := make([]bool, len(y))
+	for i := range x {
+		p := e.child(path, strconv.Itoa(i))
+		found := false
+		for k := range y {
+			if !used[k] && e.equal(p, x[i], y[k]) {
+				used[k] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package simplejson
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	js := mustJson(t, `{"count": 10, "ratio": 0.5, "tags": ["a", "b"], "meta": {"ok": true, "none": null}}`)
+	other := New()
+	other.Set("count", 10)
+	other.Set("ratio", float32(0.5))
+	other.Set("tags", []interface{}{"a", "b"})
+	other.Set("meta", map[string]interface{}{"ok": true, "none": nil})
+
+	if !js.Equal(other) || !other.Equal(js) {
+		t.Errorf("expected equal")
+	}
+
+	other.Set("tags", []string{"a", "b"})
+	if !js.Equal(other) || !other.Equal(js) {
+		t.Errorf("expected equal")
+	}
+
+	other.Get("meta").Del("none")
+	if js.Equal(other) {
+		t.Errorf("missing key should differ from null")
+	}
+
+	nan := New()
+	nan.Set("n", math.NaN())
+	if nan.Equal(mustJson(t, `{"n": 1}`)) || nan.Equal(nan.Clone()) {
+		t.Errorf("NaN should equal nothing")
+	}
+
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{`1`, `1.0`, true},
+		{`1e2`, `100`, true},
+		{`18446744073709551615`, `18446744073709551615`, true},
+		{`9223372036854775807`, `9223372036854775806`, false},
+		{`-1`, `18446744073709551615`, false},
+		{`"1"`, `1`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`[1]`, `[1, 1]`, false},
+		{`null`, `{}`, false},
+		{`{"a": [{"b": 1}]}`, `{"a": [{"b": 1.0}]}`, true},
+	}
+	for _, tc := range cases {
+		if eq := mustJson(t, tc.a).Equal(mustJson(t, tc.b)); eq != tc.equal {
+			t.Errorf("%s == %s: got %#v", tc.a, tc.b, eq)
+		}
+	}
+}
+
+func TestEqualWith(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		opts  EqualOptions
+		equal bool
+	}{
+		{`[1, 2, 2]`, `[2, 1, 2]`, EqualOptions{IgnoreArrayOrder: true}, true},
+		{`[1, 1, 2]`, `[2, 1, 2]`, EqualOptions{IgnoreArrayOrder: true}, false},
+		{`{"a": [[1, 2], [3]]}`, `{"a": [[3], [2, 1]]}`, EqualOptions{IgnoreArrayOrder: true}, true},
+		{`{"a": 1, "t": 1}`, `{"a": 1, "t": 2}`, EqualOptions{IgnorePaths: []string{"/t"}}, true},
+		{`{"a": 1, "t": 1}`, `{"a": 1}`, EqualOptions{IgnorePaths: []string{"/t"}}, true},
+		{`{"a": 1}`, `{"a": 1, "t": 1}`, EqualOptions{IgnorePaths: []string{"/t"}}, true},
+		{`{"a": 2, "t": 1}`, `{"a": 1}`, EqualOptions{IgnorePaths: []string{"/t"}}, false},
+		{
+			`{"items": [{"id": 1, "at": "x"}, {"id": 2, "at": "y"}]}`,
+			`{"items": [{"id": 1, "at": "z"}, {"id": 2}]}`,
+			EqualOptions{IgnorePaths: []string{"/items/*/at"}},
+			true,
+		},
+		{`{"a/b": 1}`, `{"a/b": 2}`, EqualOptions{IgnorePaths: []string{"/a~1b"}}, true},
+		{`{"a": 1}`, `{"b": 2}`, EqualOptions{IgnorePaths: []string{""}}, true},
+	}
+	for _, tc := range cases {
+		if eq := mustJson(t, tc.a).EqualWith(mustJson(t, tc.b), tc.opts); eq != tc.equal {
+			t.Errorf("%s == %s (%+v): got %#v", tc.a, tc.b, tc.opts, eq)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	mustJson(t, `{}`).EqualWith(mustJson(t, `{}`), EqualOptions{IgnorePaths: []string{"t"}})
+}
@@ -0,0 +1,108 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	// ErrNotFound is returned when reading a value that does not exist,
+	// such as the result of Get for a key that is not present
+	ErrNotFound = errors.New("not found")
+	// ErrTypeMismatch is returned when a value exists but is not of
+	// the JSON type the accessor reads
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// PathError records which value an accessor failed to read and why
+//
+// use errors.As to inspect it and errors.Is to test for ErrNotFound,
+// ErrTypeMismatch or one of the number conversion errors:
+//
+//	port, err := js.Get("server").Get("port").Int()
+//	var perr *simplejson.PathError
+//	if errors.As(err, &perr) {
+//		log.Printf("%s: expected %s, got %s", perr.Path, perr.Expected, perr.Actual)
+//	}
+//
+// the accessors for objects and arrays only read decoded data, so for a Go
+// map, slice or struct stored with Set, Actual is its Go type, e.g. "[]int".
+type PathError struct {
+	Path     string // RFC 6901 JSON Pointer to the value, "" for the root
+	Expected string // the Kind the accessor reads, e.g. "number"
+	Actual   string // the Kind found, "missing" if there is no value
+	Err      error
+}
+
+func (e *PathError) Error() string {
+	switch e.Err {
+	case ErrNotFound:
+		return fmt.Sprintf("json value %q: %s", e.Path, e.Err)
+	case ErrTypeMismatch:
+		return fmt.Sprintf("json value %q: expected %s, got %s", e.Path, e.Expected, e.Actual)
+	}
+	if cause, ok := e.Err.(*PathError); ok {
+		if cause.Err == ErrTypeMismatch {
+			return fmt.Sprintf("json value %q: not found: %q is %s, not %s", e.Path, cause.Path, cause.Actual, cause.Expected)
+		}
+		return fmt.Sprintf("json value %q: not found: %q does not exist", e.Path, cause.Path)
+	}
+	return fmt.Sprintf("json value %q: %s", e.Path, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports a missing value as ErrNotFound, even when the traversal
+// failed further up because of a type mismatch
+func (e *PathError) Is(target error) bool {
+	return target == ErrNotFound && e.Actual == Missing.String()
+}
+
+// valueError returns a `*PathError` explaining why the value of `j` could
+// not be read as `expected`
+//
+// for a missing value, `err` is ignored in favour of the traversal failure
+// recorded by Get or GetIndex, which is wrapped when it happened higher up
+func (j *Json) valueError(expected string, err error) error {
+	if j.err == nil {
+		return &PathError{Path: j.pointer(), Expected: expected, Actual: valueKind(j.data), Err: err}
+	}
+	path := j.pointer()
+	if cause, ok := j.err.(*PathError); ok && cause.Path == path {
+		return &PathError{Path: path, Expected: expected, Actual: Missing.String(), Err: cause.Err}
+	}
+	return &PathError{Path: path, Expected: expected, Actual: Missing.String(), Err: j.err}
+}
+
+// valueKind describes `v` for PathError.Actual: its Kind, except for Go
+// maps, slices and structs stored with Set, which are named by their type
+// since the accessors for objects and arrays only read decoded data
+func valueKind(v interface{}) string {
+	k := kindOf(v)
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		if k == Object || k == Array {
+			return fmt.Sprintf("%T", v)
+		}
+	}
+	return k.String()
+}
+
+// pointer renders the location of `j` within the document it was
+// obtained from as a JSON Pointer
+func (j *Json) pointer() string {
+	if j.parent == nil {
+		return ""
+	}
+	switch k := j.key.(type) {
+	case string:
+		return j.parent.pointer() + "/" + escapePointerToken(k)
+	case int:
+		return j.parent.pointer() + "/" + strconv.Itoa(k)
+	}
+	return j.parent.pointer()
+}
@@ -0,0 +1,157 @@
+package simplejson
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPathError(t *testing.T) {
+	js := mustJson(t, `{
+		"server": {"port": "80", "hosts": ["a", 2], "a/b": null},
+		"big": 1e400,
+		"neg": -1
+	}`)
+
+	for name, tc := range map[string]struct {
+		err      error
+		sentinel error
+		path     string
+		expected string
+		actual   string
+	}{
+		"missing": {
+			func() error { _, err := js.Get("server").Get("timeout").Int(); return err }(),
+			ErrNotFound, "/server/timeout", "number", "missing",
+		},
+		"missing parent": {
+			func() error { _, err := js.GetPath("nope", "x").String(); return err }(),
+			ErrNotFound, "/nope/x", "string", "missing",
+		},
+		"mismatch": {
+			func() error { _, err := js.Get("server").Get("port").Int(); return err }(),
+			ErrTypeMismatch, "/server/port", "number", "string",
+		},
+		"null": {
+			func() error { _, err := js.Get("server").Get("a/b").Map(); return err }(),
+			ErrTypeMismatch, "/server/a~1b", "object", "null",
+		},
+		"element": {
+			func() error { _, err := js.Get("server").Get("hosts").StringArray(); return err }(),
+			ErrTypeMismatch, "/server/hosts/1", "string", "number",
+		},
+		"index": {
+			func() error { _, err := js.Get("server").Get("hosts").GetIndex(5).Bool(); return err }(),
+			ErrNotFound, "/server/hosts/5", "boolean", "missing",
+		},
+		"go value": {
+			func() error {
+				js.Set("go", []int{1, 2})
+				_, err := js.Get("go").Array()
+				return err
+			}(),
+			ErrTypeMismatch, "/go", "array", "[]int",
+		},
+		"root": {
+			func() error { _, err := js.Array(); return err }(),
+			ErrTypeMismatch, "", "array", "object",
+		},
+		"range": {
+			func() error { _, err := js.Get("big").Float64(); return err }(),
+			strconv.ErrRange, "/big", "number", "number",
+		},
+		"negative": {
+			func() error { _, err := As[uint](js.Get("neg")); return err }(),
+			ErrNegative, "/neg", "number", "number",
+		},
+		"loose": {
+			func() error { _, err := js.Get("server").Get("hosts").GetIndex(0).IntLoose(); return err }(),
+			ErrTypeMismatch, "/server/hosts/0", "number", "string",
+		},
+	} {
+		var perr *PathError
+		if !errors.As(tc.err, &perr) {
+			t.Errorf("%s: got %#v", name, tc.err)
+			continue
+		}
+		if !errors.Is(tc.err, tc.sentinel) {
+			t.Errorf("%s: got %v", name, tc.err)
+		}
+		if perr.Path != tc.path || perr.Expected != tc.expected || perr.Actual != tc.actual {
+			t.Errorf("%s: got %#v", name, perr)
+		}
+	}
+
+	_, err := js.Get("server").Get("port").Bool()
+	if err == nil || err.Error() != `json value "/server/port": expected boolean, got string` {
+		t.Errorf("got %v", err)
+	}
+	_, err = js.Get("server").Get("timeout").Bool()
+	if err == nil || err.Error() != `json value "/server/timeout": not found` {
+		t.Errorf("got %v", err)
+	}
+
+	// a value that is set stops being missing
+	timeout := js.Get("server").Get("timeout")
+	timeout.SetPath(nil, 30)
+	if _, err := timeout.Int(); err != nil {
+		t.Errorf("err %v", err)
+	}
+
+	if _, err := js.GetPointer("/server/nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := js.GetPointer("/big/0"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestChainedErrors(t *testing.T) {
+	js := mustJson(t, `{"a": {"b": "str", "c": [1, 2], "n": null}}`)
+
+	for name, tc := range map[string]struct {
+		j        *Json
+		msg      string
+		mismatch bool
+	}{
+		"missing key": {
+			js.Get("x").Get("b").GetIndex(2),
+			`json value "/x/b/2": not found: "/x" does not exist`, false,
+		},
+		"not an object": {
+			js.Get("a").Get("b").Get("c"),
+			`json value "/a/b/c": not found: "/a/b" is string, not object`, true,
+		},
+		"not an array": {
+			js.GetPath("a", "b").GetIndex(2),
+			`json value "/a/b/2": not found: "/a/b" is string, not array`, true,
+		},
+		"index": {
+			js.Get("a").Get("c").GetIndex(2),
+			`json value "/a/c/2": not found`, false,
+		},
+		"negative index": {
+			js.Get("a").Get("c").GetIndex(-1),
+			`json value "/a/c/-1": not found`, false,
+		},
+	} {
+		err := tc.j.Err()
+		if err == nil || err.Error() != tc.msg {
+			t.Errorf("%s: got %v", name, err)
+		}
+		_, err = tc.j.Int()
+		if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrTypeMismatch) != tc.mismatch {
+			t.Errorf("%s: got %v", name, err)
+		}
+		var perr *PathError
+		if !errors.As(err, &perr) || perr.Expected != "number" || perr.Actual != "missing" {
+			t.Errorf("%s: got %#v", name, perr)
+		}
+	}
+
+	for _, j := range []*Json{js, js.Get("a"), js.Get("a").Get("n"), js.Get("a").Get("c").GetIndex(1)} {
+		if err := j.Err(); err != nil {
+			t.Errorf("err %v", err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package simplejson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field as seen by encoding/json, which Decode reads
+// into and normalize converts to an object member
+type field struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+type structFields struct {
+	list   []field
+	byName map[string]*field
+}
+
+// lookup finds the field for an object member, preferring an
+// exact match and falling back to a case-insensitive one
+func (s *structFields) lookup(name string) *field {
+	if f, ok := s.byName[name]; ok {
+		return f
+	}
+	for i := range s.list {
+		if strings.EqualFold(s.list[i].name, name) {
+			return &s.list[i]
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+func cachedTypeFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+// typeFields returns the fields that encoding/json would use for `t`,
+// in encoding order, applying its rules for embedded structs: a field at
+// a shallower depth wins, then a tagged one, and otherwise both are dropped
+func typeFields(t reflect.Type) *structFields {
+	type entry struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []field
+	visited := map[reflect.Type]bool{}
+	next := []entry{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, entry{ft, index})
+					continue
+				}
+				f := field{name: name, index: index, tagged: name != ""}
+				if name == "" {
+					f.name = sf.Name
+				}
+				for _, opt := range strings.Split(opts, ",") {
+					switch opt {
+					case "omitempty":
+						f.omitEmpty = true
+					case "string":
+						switch ft.Kind() {
+						case reflect.Bool, reflect.String,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64:
+							f.quoted = true
+						}
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, k int) bool {
+		if fields[i].name != fields[k].name {
+			return fields[i].name < fields[k].name
+		}
+		if len(fields[i].index) != len(fields[k].index) {
+			return len(fields[i].index) < len(fields[k].index)
+		}
+		return fields[i].tagged && !fields[k].tagged
+	})
+	var dominant []field
+	for i := 0; i < len(fields); {
+		k := i + 1
+		for k < len(fields) && fields[k].name == fields[i].name {
+			k++
+		}
+		group := fields[i:k]
+		i = k
+		if len(group) > 1 && len(group[0].index) == len(group[1].index) && group[0].tagged == group[1].tagged {
+			continue
+		}
+		dominant = append(dominant, group[0])
+	}
+	sort.Slice(dominant, func(i, k int) bool {
+		a, b := dominant[i].index, dominant[k].index
+		for x := 0; x < len(a) && x < len(b); x++ {
+			if a[x] != b[x] {
+				return a[x] < b[x]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	s := &structFields{list: dominant, byName: make(map[string]*field, len(dominant))}
+	for i := range s.list {
+		s.byName[s.list[i].name] = &s.list[i]
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package simplejson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeFields(t *testing.T) {
+	type inner struct {
+		A int
+		B int `json:"b"`
+	}
+	type other struct {
+		A int
+	}
+	type outer struct {
+		inner
+		other
+		B int
+		C int `json:"c,omitempty"`
+	}
+
+	fields := typeFields(reflect.TypeOf(outer{})).list
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+	// A is ambiguous between the embedded structs, while b and B
+	// differ in case and so are distinct names
+	if !reflect.DeepEqual(names, []string{"b", "B", "c"}) {
+		t.Errorf("got %#v", names)
+	}
+	if !fields[2].omitEmpty {
+		t.Errorf("expected omitempty")
+	}
+}
@@ -0,0 +1,247 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrKeyConflict is returned by Unflatten when two keys describe
+	// incompatible values, such as `a` and `a.b` or `a.0` and `a.b`
+	ErrKeyConflict = errors.New("conflicts with another key")
+
+	errFlattenKey   = errors.New("malformed key")
+	errFlattenIndex = errors.New("array index out of range")
+)
+
+// IndexStyle selects how Flatten renders array indices
+type IndexStyle int
+
+const (
+	// IndexSeparated treats indices like keys, as in `a.0.c`
+	IndexSeparated IndexStyle = iota
+	// IndexBracketed renders indices in brackets, as in `a[0].c`
+	IndexBracketed
+)
+
+// FlattenOptions configures Flatten and UnflattenWith
+type FlattenOptions struct {
+	Separator  string // placed between keys, "." if empty
+	IndexStyle IndexStyle
+}
+
+func (o FlattenOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// Flatten returns every value in `Json` that is not an object or array keyed
+// by its path, with the keys along the path joined by the separator
+//
+// empty objects and arrays are kept as values so that Unflatten can restore
+// them. Keys that contain the separator (or brackets, with IndexBracketed)
+// cannot be told apart from nesting when unflattening, and with IndexSeparated
+// neither can keys such as "0" that look like array indices:
+//
+//	js.Flatten(simplejson.FlattenOptions{})
+//	// {"a.b.0.c": 1}
+//	js.Flatten(simplejson.FlattenOptions{Separator: "_", IndexStyle: simplejson.IndexBracketed})
+//	// {"a_b[0]_c": 1}
+func (j *Json) Flatten(opts FlattenOptions) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flatten(Path{}, j.data, opts, flat)
+	return flat
+}
+
+func flatten(path Path, v interface{}, opts FlattenOptions, flat map[string]interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 0 {
+			flat[opts.format(path)] = map[string]interface{}{}
+		}
+		for k, mv := range x {
+			flatten(append(path[:len(path):len(path)], k), mv, opts, flat)
+		}
+	case []interface{}:
+		if len(x) == 0 {
+			flat[opts.format(path)] = []interface{}{}
+		}
+		for i, av := range x {
+			flatten(append(path[:len(path):len(path)], i), av, opts, flat)
+		}
+	default:
+		flat[opts.format(path)] = v
+	}
+}
+
+// format renders `path` as a flattened key
+func (o FlattenOptions) format(path Path) string {
+	var b strings.Builder
+	for i, seg := range path {
+		switch s := seg.(type) {
+		case string:
+			if i > 0 {
+				b.WriteString(o.separator())
+			}
+			b.WriteString(s)
+		case int:
+			if o.IndexStyle == IndexBracketed {
+				b.WriteString("[" + strconv.Itoa(s) + "]")
+				continue
+			}
+			if i > 0 {
+				b.WriteString(o.separator())
+			}
+			b.WriteString(strconv.Itoa(s))
+		}
+	}
+	return b.String()
+}
+
+// parse splits a flattened key back into its path; with IndexSeparated
+// any segment that is a valid array index is taken to be one
+func (o FlattenOptions) parse(key string) (Path, error) {
+	if key == "" {
+		return Path{}, nil
+	}
+	var path Path
+	for _, part := range strings.Split(key, o.separator()) {
+		if o.IndexStyle == IndexSeparated {
+			if idx, ok := pointerIndex(part); ok {
+				path = append(path, idx)
+			} else {
+				path = append(path, part)
+			}
+			continue
+		}
+
+		name, rest, bracketed := strings.Cut(part, "[")
+		if name != "" || !bracketed || len(path) > 0 {
+			path = append(path, name)
+		}
+		for bracketed {
+			var tok string
+			tok, rest, bracketed = strings.Cut(rest, "]")
+			idx, ok := pointerIndex(tok)
+			if !bracketed || !ok {
+				return nil, errFlattenKey
+			}
+			path = append(path, idx)
+			if rest == "" {
+				break
+			}
+			if rest[0] != '[' {
+				return nil, errFlattenKey
+			}
+			rest = rest[1:]
+		}
+	}
+	return path, nil
+}
+
+// Unflatten returns a pointer to a new `Json` object rebuilt from a map
+// produced by Flatten with the default options
+func Unflatten(flat map[string]interface{}) (*Json, error) {
+	return UnflattenWith(flat, FlattenOptions{})
+}
+
+// UnflattenWith returns a pointer to a new `Json` object rebuilt from a map
+// of flattened keys, as produced by Flatten with the same options
+//
+// objects and arrays are created as the keys require, with any array elements
+// that no key sets left null; an index must be less than the number of keys in
+// `flat`, which bounds the size of the arrays. An error wrapping ErrKeyConflict
+// is returned if two keys need different values at the same location, which
+// includes a key below one whose value is a non-empty object or array, as in
+// `a` and `a.b` for {"a": {"c": 1}, "a.b": 2}:
+//
+//	js, err := simplejson.UnflattenWith(map[string]interface{}{
+//		"DB__HOSTS__0": "a",
+//		"DB__HOSTS__1": "b",
+//	}, simplejson.FlattenOptions{Separator: "__"})
+func UnflattenWith(flat map[string]interface{}, opts FlattenOptions) (*Json, error) {
+	var root interface{}
+	placed := make(map[string]bool, len(flat))
+	for _, key := range sortedKeys(flat) {
+		path, err := opts.parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("unflatten %q: %w", key, err)
+		}
+		root, err = unflattenInsert(root, path, 0, deepCopy(flat[key]), len(flat), placed)
+		if err != nil {
+			return nil, fmt.Errorf("unflatten %q: %w", key, err)
+		}
+	}
+	return &Json{data: root}, nil
+}
+
+// unflattenInsert stores `val` at `path[i:]` below `node`, returning the
+// updated node; `placed` records the JSON Pointers of the values stored so
+// far, so that they are not mistaken for containers created along the way
+func unflattenInsert(node interface{}, path Path, i int, val interface{}, limit int, placed map[string]bool) (interface{}, error) {
+	ptr := branchPointer(path[:i])
+	if placed[ptr] {
+		return nil, ErrKeyConflict
+	}
+	if i == len(path) {
+		if node != nil {
+			return nil, ErrKeyConflict
+		}
+		// an empty object or array, as Flatten renders
+		// them, may be filled in by other keys
+		switch x := val.(type) {
+		case map[string]interface{}:
+			if len(x) == 0 {
+				return val, nil
+			}
+		case []interface{}:
+			if len(x) == 0 {
+				return val, nil
+			}
+		}
+		placed[ptr] = true
+		return val, nil
+	}
+
+	switch seg := path[i].(type) {
+	case string:
+		if node == nil {
+			node = make(map[string]interface{})
+		}
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, ErrKeyConflict
+		}
+		child, err := unflattenInsert(m[seg], path, i+1, val, limit, placed)
+		if err != nil {
+			return nil, err
+		}
+		m[seg] = child
+		return m, nil
+	case int:
+		if seg >= limit {
+			return nil, errFlattenIndex
+		}
+		if node == nil {
+			node = []interface{}{}
+		}
+		a, ok := node.([]interface{})
+		if !ok {
+			return nil, ErrKeyConflict
+		}
+		for len(a) <= seg {
+			a = append(a, nil)
+		}
+		child, err := unflattenInsert(a[seg], path, i+1, val, limit, placed)
+		if err != nil {
+			return nil, err
+		}
+		a[seg] = child
+		return a, nil
+	}
+	return nil, errFlattenKey
+}
@@ -0,0 +1,133 @@
+package simplejson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	js := mustJson(t, `{
+		"a": {"b": [{"c": 1}, 2]},
+		"empty": {},
+		"none": [],
+		"n": null,
+		"s": "x"
+	}`)
+
+	flat := js.Flatten(FlattenOptions{})
+	expected := map[string]interface{}{
+		"a.b.0.c": 1,
+		"a.b.1":   2,
+		"empty":   map[string]interface{}{},
+		"none":    []interface{}{},
+		"n":       nil,
+		"s":       "x",
+	}
+	if !valuesEqual(flat, expected) {
+		t.Errorf("got %#v", flat)
+	}
+
+	flat = js.Flatten(FlattenOptions{Separator: "_", IndexStyle: IndexBracketed})
+	if _, ok := flat["a_b[0]_c"]; !ok {
+		t.Errorf("got %#v", flat)
+	}
+	if _, ok := flat["a_b[1]"]; !ok {
+		t.Errorf("got %#v", flat)
+	}
+
+	if flat := mustJson(t, `[[1], 2]`).Flatten(FlattenOptions{IndexStyle: IndexBracketed}); !valuesEqual(flat, map[string]interface{}{"[0][0]": 1, "[1]": 2}) {
+		t.Errorf("got %#v", flat)
+	}
+	if flat := mustJson(t, `5`).Flatten(FlattenOptions{}); !valuesEqual(flat, map[string]interface{}{"": 5}) {
+		t.Errorf("got %#v", flat)
+	}
+}
+
+func TestUnflattenRoundTrip(t *testing.T) {
+	for _, doc := range []string{
+		`{"a": {"b": [{"c": 1}, 2]}, "empty": {}, "none": [], "n": null, "s": "x"}`,
+		`[[1, [2]], {"x": "y"}]`,
+		`{"digits": {"01": 1, "x": [[]]}}`,
+		`"scalar"`,
+	} {
+		js := mustJson(t, doc)
+		for _, opts := range []FlattenOptions{
+			{},
+			{Separator: "__"},
+			{Separator: "/", IndexStyle: IndexBracketed},
+		} {
+			back, err := UnflattenWith(js.Flatten(opts), opts)
+			if err != nil {
+				t.Errorf("%s %#v: err %v", doc, opts, err)
+				continue
+			}
+			if !back.Equal(js) {
+				t.Errorf("%s %#v: got %#v", doc, opts, back.Interface())
+			}
+		}
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	js, err := UnflattenWith(map[string]interface{}{
+		"DB__HOSTS__0": "a",
+		"DB__HOSTS__2": "c",
+		"DB__PORT":     5432,
+	}, FlattenOptions{Separator: "__"})
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if !js.Equal(mustJson(t, `{"DB": {"HOSTS": ["a", null, "c"], "PORT": 5432}}`)) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	// bracketed indices leave numeric keys as object members
+	js, err = UnflattenWith(map[string]interface{}{"a.0": 1, "a.1[0]": 2}, FlattenOptions{IndexStyle: IndexBracketed})
+	if err != nil || !js.Equal(mustJson(t, `{"a": {"0": 1, "1": [2]}}`)) {
+		t.Errorf("got %#v %v", js, err)
+	}
+
+	// values are copied
+	inner := map[string]interface{}{}
+	js, _ = Unflatten(map[string]interface{}{"a": inner})
+	js.Get("a").Set("x", 1)
+	if len(inner) != 0 {
+		t.Errorf("input was modified")
+	}
+
+	for name, flat := range map[string]map[string]interface{}{
+		"leaf then child": {"a": 1, "a.b": 2},
+		"null then child": {"a": nil, "a.b": 2},
+		"array vs object": {"a.0": 1, "a.b": 2},
+		"value vs child":  {"a": map[string]interface{}{"b": 1}, "a.b": 2},
+		"object vs child": {"a": map[string]interface{}{"c": 1}, "a.b": 2},
+		"array vs child":  {"a": []interface{}{1}, "a.1": 2},
+		"root vs child":   {"": 1, "a": 2},
+	} {
+		if _, err := Unflatten(flat); !errors.Is(err, ErrKeyConflict) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+
+	for name, flat := range map[string]map[string]interface{}{
+		"huge index":      {"a.1000000000": 1},
+		"bad bracket":     {"a[x]": 1},
+		"unclosed":        {"a[0": 1},
+		"trailing suffix": {"a[0]b": 1},
+	} {
+		_, err := UnflattenWith(flat, FlattenOptions{IndexStyle: IndexBracketed})
+		if name == "huge index" {
+			_, err = Unflatten(flat)
+		}
+		if err == nil || errors.Is(err, ErrKeyConflict) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+
+	// an empty container may be merged with keys below it
+	js, err = Unflatten(map[string]interface{}{"a": map[string]interface{}{}, "a.b": 1})
+	if err != nil || !reflect.DeepEqual(js.Get("a").Keys(), []string{"b"}) {
+		t.Errorf("got %#v %v", js, err)
+	}
+}
@@ -0,0 +1,184 @@
+package simplejson
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+)
+
+// As converts the underlying data to `T`, which may be any of the
+// numeric kinds, `string`, `bool`, `interface{}`, or a slice or
+// `map[string]` of those (nested to any depth)
+//
+// numbers are coerced between representations like Int64 and friends,
+// truncating fractions (so 3.9 and 2.5e2 become 3 and 250 as integers),
+// but a value that does not fit in `T` is an ErrOutOfRange or ErrNegative
+// error rather than being silently wrapped.
+// Null array elements become the zero value, as they do in StringArray.
+// Errors are a `*PathError` locating the offending element:
+//
+//	port, err := simplejson.As[uint16](js.Get("port"))
+//	ids, err := simplejson.As[[]int](js.Get("ids"))
+//	labels, err := simplejson.As[map[string]string](js.Get("labels"))
+func As[T any](j *Json) (T, error) {
+	var t T
+	err := convertValue(j, reflect.ValueOf(&t).Elem())
+	return t, err
+}
+
+// MustAs guarantees the return of a `T` (with optional default)
+//
+// useful when you explicitly want a `T` in a single value return context:
+//
+//	myFunc(simplejson.MustAs[int32](js.Get("param1")), simplejson.MustAs(js.Get("optional_param"), float32(5.150)))
+func MustAs[T any](j *Json, args ...T) T {
+	t, err := As[T](j)
+	return mustDefault("MustAs", args, t, err)
+}
+
+// mustDefault implements the optional default argument shared by the
+// Must family, panicking when more than one default is given
+func mustDefault[T any](name string, args []T, v T, err error) T {
+	var def T
+
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("%s() received too many arguments %d", name, len(args))
+	}
+
+	if err == nil {
+		return v
+	}
+
+	return def
+}
+
+// expectedKind names the JSON type that converts to `t`
+func expectedKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Interface:
+		return "any"
+	case reflect.Ptr:
+		return expectedKind(t.Elem())
+	}
+	return "number"
+}
+
+func convertValue(j *Json, dst reflect.Value) error {
+	t := dst.Type()
+	if j.err != nil {
+		return j.valueError(expectedKind(t), ErrNotFound)
+	}
+	v := j.data
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			break
+		}
+		if v != nil {
+			dst.Set(reflect.ValueOf(v))
+		}
+		return nil
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return j.valueError("string", ErrTypeMismatch)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return j.valueError("boolean", ErrTypeMismatch)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := coerceInt64(v, false)
+		if err != nil {
+			return j.valueError("number", err)
+		}
+		if dst.OverflowInt(i) {
+			return j.valueError("number", fmt.Errorf("%w: %d does not fit in %s", ErrOutOfRange, i, t))
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := coerceUint64(v, false)
+		if err != nil {
+			return j.valueError("number", err)
+		}
+		if dst.OverflowUint(u) {
+			return j.valueError("number", fmt.Errorf("%w: %d does not fit in %s", ErrOutOfRange, u, t))
+		}
+		dst.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := j.Float64()
+		if err != nil {
+			return err
+		}
+		if !math.IsInf(f, 0) && dst.OverflowFloat(f) {
+			return j.valueError("number", fmt.Errorf("%w: %g does not fit in %s", ErrOutOfRange, f, t))
+		}
+		dst.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if s, ok := v.(string); ok {
+				dst.SetBytes([]byte(s))
+				return nil
+			}
+		}
+		a, ok := v.([]interface{})
+		if !ok {
+			return j.valueError("array", ErrTypeMismatch)
+		}
+		ret := reflect.MakeSlice(t, len(a), len(a))
+		for i, e := range a {
+			if e == nil {
+				continue
+			}
+			if err := convertValue(&Json{data: e, parent: j, key: i}, ret.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(ret)
+		return nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			break
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return j.valueError("object", ErrTypeMismatch)
+		}
+		ret := reflect.MakeMapWithSize(t, len(m))
+		elem := reflect.New(t.Elem()).Elem()
+		for k, e := range m {
+			elem.Set(reflect.Zero(t.Elem()))
+			if e != nil {
+				if err := convertValue(&Json{data: e, parent: j, key: k}, elem); err != nil {
+					return err
+				}
+			}
+			ret.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+		}
+		dst.Set(ret)
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
@@ -0,0 +1,128 @@
+package simplejson
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	js := mustJson(t, `{
+		"int": 10,
+		"neg": -3,
+		"big": 300,
+		"float": 5.150,
+		"huge": 1e300,
+		"string": "simplejson",
+		"bool": true,
+		"ints": [1, 2, null],
+		"nested": [[1], [2, 3]],
+		"labels": {"a": "x", "b": "y"},
+		"counts": {"a": 1, "b": 2},
+		"mixed": [1, "2"],
+		"frac": 3.9,
+		"exp": [1e3, 2.5e2, -2.5, 1e-400, -0.5]
+	}`)
+	js.Set("go_uint", uint64(math.MaxUint64))
+
+	if v, err := As[int32](js.Get("int")); err != nil || v != 10 {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[uint8](js.Get("int")); err != nil || v != 10 {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[float32](js.Get("float")); err != nil || v != 5.150 {
+		t.Errorf("got %#v %v", v, err)
+	}
+	// decoded numbers are truncated like Go floats, whatever their syntax
+	if v, err := As[int](js.Get("frac")); err != nil || v != 3 {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[[]int](js.Get("exp")); err != nil || !reflect.DeepEqual(v, []int{1000, 250, -2, 0, 0}) {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[uint8](js.Get("exp").GetIndex(1)); err != nil || v != 250 {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if _, err := js.Get("frac").IntStrict(); !errors.Is(err, ErrFractional) {
+		t.Errorf("got %v", err)
+	}
+	if v, err := As[string](js.Get("string")); err != nil || v != "simplejson" {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[bool](js.Get("bool")); err != nil || !v {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[[]int](js.Get("ints")); err != nil || !reflect.DeepEqual(v, []int{1, 2, 0}) {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[[][]uint](js.Get("nested")); err != nil || !reflect.DeepEqual(v, [][]uint{{1}, {2, 3}}) {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[map[string]string](js.Get("labels")); err != nil || !reflect.DeepEqual(v, map[string]string{"a": "x", "b": "y"}) {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[map[string]int64](js.Get("counts")); err != nil || !reflect.DeepEqual(v, map[string]int64{"a": 1, "b": 2}) {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[[]byte](js.Get("string")); err != nil || string(v) != "simplejson" {
+		t.Errorf("got %#v %v", v, err)
+	}
+	if v, err := As[interface{}](js.Get("string")); err != nil || v != "simplejson" {
+		t.Errorf("got %#v %v", v, err)
+	}
+
+	for name, tc := range map[string]struct {
+		err      error
+		expected error
+	}{
+		"int8":    {asErr[int8](js.Get("big")), ErrOutOfRange},
+		"uint":    {asErr[uint](js.Get("neg")), ErrNegative},
+		"float32": {asErr[float32](js.Get("huge")), ErrOutOfRange},
+		"int64":   {asErr[int64](js.Get("go_uint")), ErrOutOfRange},
+		"huge":    {asErr[int64](js.Get("huge")), ErrOutOfRange},
+		"exp":     {asErr[int8](js.Get("exp").GetIndex(0)), ErrOutOfRange},
+		"negfrac": {asErr[uint](js.Get("exp").GetIndex(2)), ErrNegative},
+	} {
+		if !errors.Is(tc.err, tc.expected) {
+			t.Errorf("%s: got %v", name, tc.err)
+		}
+	}
+
+	if err := asErr[[]int](js.Get("mixed")); err == nil || err.Error() != `json value "/mixed/1": expected number, got string` {
+		t.Errorf("got %v", err)
+	}
+	if err := asErr[map[int]int](js.Get("counts")); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func asErr[T any](j *Json) error {
+	_, err := As[T](j)
+	return err
+}
+
+func TestMustAs(t *testing.T) {
+	js := mustJson(t, `{"int": 10, "big": 300}`)
+
+	if v := MustAs[int16](js.Get("int")); v != 10 {
+		t.Errorf("got %#v", v)
+	}
+	if v := MustAs(js.Get("big"), int8(5)); v != 5 {
+		t.Errorf("got %#v", v)
+	}
+	if v := MustAs(js.Get("missing"), []string{"a"}); !reflect.DeepEqual(v, []string{"a"}) {
+		t.Errorf("got %#v", v)
+	}
+	if v := MustAs[float32](js.Get("missing")); v != 0 {
+		t.Errorf("got %#v", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	MustAs(js.Get("int"), 1, 2)
+}
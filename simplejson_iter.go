@@ -0,0 +1,94 @@
+package simplejson
+
+import (
+	"iter"
+)
+
+// Entries returns an iterator over the members of a `Json` object, yielding
+// each key with a `Json` for its value, in document order if `Json` was
+// created by NewOrderedJson and in no particular order otherwise
+//
+// like Get, each value remembers its location so it can be modified in
+// place. Nothing is yielded if `Json` is not an object:
+//
+//	for key, val := range js.Get("headers").Entries() {
+//		fmt.Println(key, val.MustString())
+//	}
+func (j *Json) Entries() iter.Seq2[string, *Json] {
+	return func(yield func(string, *Json) bool) {
+		m, ok := j.data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if o := j.keyOrder(); o != nil {
+			for _, k := range o.keys(m) {
+				if !yield(k, &Json{data: m[k], parent: j, parentGen: j.gen, key: k}) {
+					return
+				}
+			}
+			return
+		}
+		for k, v := range m {
+			if !yield(k, &Json{data: v, parent: j, parentGen: j.gen, key: k}) {
+				return
+			}
+		}
+	}
+}
+
+// SortedEntries is Entries in ascending key order, for deterministic output
+func (j *Json) SortedEntries() iter.Seq2[string, *Json] {
+	return func(yield func(string, *Json) bool) {
+		m, ok := j.data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, k := range sortedKeys(m) {
+			if !yield(k, &Json{data: m[k], parent: j, parentGen: j.gen, key: k}) {
+				return
+			}
+		}
+	}
+}
+
+// Elements returns an iterator over the elements of a `Json` array,
+// yielding each index with a `Json` for its value
+//
+// like GetIndex, each element remembers its location so it can be
+// modified in place. Nothing is yielded if `Json` is not an array
+func (j *Json) Elements() iter.Seq2[int, *Json] {
+	return func(yield func(int, *Json) bool) {
+		a, ok := j.data.([]interface{})
+		if !ok {
+			return
+		}
+		for i, v := range a {
+			if !yield(i, &Json{data: v, parent: j, parentGen: j.gen, key: i}) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the keys of a `Json` object, in document order if `Json`
+// was created by NewOrderedJson and in ascending order otherwise, or nil
+// if it is not an object
+func (j *Json) Keys() []string {
+	m, ok := j.data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return j.keyOrder().keys(m)
+}
+
+// Len returns the number of members of a `Json` object or elements
+// of a `Json` array, and 0 for any other value
+func (j *Json) Len() int {
+	switch v := j.data.(type) {
+	case map[string]interface{}:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	}
+	return 0
+}
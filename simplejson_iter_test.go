@@ -0,0 +1,73 @@
+package simplejson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntries(t *testing.T) {
+	js := mustJson(t, `{"obj": {"b": 2, "a": 1, "c": 3}, "arr": ["x", "y", "z"], "str": "s"}`)
+
+	seen := map[string]int{}
+	for k, v := range js.Get("obj").Entries() {
+		seen[k] = v.MustInt()
+	}
+	if !reflect.DeepEqual(seen, map[string]int{"a": 1, "b": 2, "c": 3}) {
+		t.Errorf("got %#v", seen)
+	}
+
+	var keys []string
+	for k, v := range js.Get("obj").SortedEntries() {
+		keys = append(keys, k)
+		v.SetPath(nil, v.MustInt()*10)
+		if k == "b" {
+			break
+		}
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("got %#v", keys)
+	}
+	if m := js.Get("obj").Interface(); !valuesEqual(m, map[string]interface{}{"a": 10, "b": 20, "c": 3}) {
+		t.Errorf("got %#v", m)
+	}
+
+	var elems []string
+	for i, v := range js.Get("arr").Elements() {
+		if i != len(elems) {
+			t.Errorf("got index %d", i)
+		}
+		elems = append(elems, v.MustString())
+	}
+	if !reflect.DeepEqual(elems, []string{"x", "y", "z"}) {
+		t.Errorf("got %#v", elems)
+	}
+
+	for range js.Get("str").Entries() {
+		t.Errorf("expected no entries")
+	}
+	for range js.Get("obj").Elements() {
+		t.Errorf("expected no elements")
+	}
+	for range js.Get("missing").SortedEntries() {
+		t.Errorf("expected no entries")
+	}
+}
+
+func TestKeysLen(t *testing.T) {
+	js := mustJson(t, `{"obj": {"b": 2, "a": 1}, "arr": [1, 2, 3], "str": "s"}`)
+
+	if k := js.Get("obj").Keys(); !reflect.DeepEqual(k, []string{"a", "b"}) {
+		t.Errorf("got %#v", k)
+	}
+	if k := js.Get("arr").Keys(); k != nil {
+		t.Errorf("got %#v", k)
+	}
+	for key, expected := range map[string]int{"obj": 2, "arr": 3, "str": 0, "missing": 0} {
+		if n := js.Get(key).Len(); n != expected {
+			t.Errorf("%s: got %d", key, n)
+		}
+	}
+	if n := js.Len(); n != 3 {
+		t.Errorf("got %d", n)
+	}
+}
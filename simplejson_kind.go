@@ -0,0 +1,133 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// Kind identifies the JSON type of a value, or its absence
+type Kind int
+
+const (
+	Missing Kind = iota
+	Null
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Missing:
+		return "missing"
+	case Null:
+		return "null"
+	case Bool:
+		return "boolean"
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Array:
+		return "array"
+	case Object:
+		return "object"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Kind returns the JSON type of the value, or Missing if there is none
+//
+// Go values stored with Set are classified by how Encode renders them;
+// those it cannot encode, such as channels and functions, report Null.
+// Accessors such as Map, Array and Len only read decoded objects and
+// arrays, and fail for Go maps, slices and structs.
+//
+// useful for telling a member that was sent as null from one that was omitted:
+//
+//	switch js.Get("email").Kind() {
+//	case simplejson.Missing:
+//		// leave unchanged
+//	case simplejson.Null:
+//		// clear it
+//	case simplejson.String:
+//		// update it
+//	}
+func (j *Json) Kind() Kind {
+	if !j.Exists() {
+		return Missing
+	}
+	return kindOf(j.data)
+}
+
+// Exists reports whether `Json` holds a value, including null
+//
+// it is false for the result of Get or GetIndex when the key or index does not
+// exist, and safe to call on the nil `*Json` returned by a failed CheckGet
+func (j *Json) Exists() bool {
+	return j != nil && j.err == nil
+}
+
+// IsNull reports whether `Json` holds an explicit null
+func (j *Json) IsNull() bool {
+	return j.Exists() && j.data == nil
+}
+
+// kindOf returns the JSON type of `v`, which is either decoded data or a
+// Go value stored with Set, classified by how Encode would render it
+// (ignoring any json.Marshaler implementations); values it cannot
+// render, such as channels, functions and complex numbers, are Null
+func kindOf(v interface{}) Kind {
+	switch v.(type) {
+	case nil:
+		return Null
+	case bool:
+		return Bool
+	case string:
+		return String
+	case json.Number:
+		return Number
+	case map[string]interface{}:
+		return Object
+	case []interface{}:
+		return Array
+	case []byte:
+		return String
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return Null
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return Bool
+	case reflect.String:
+		return String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return Number
+	case reflect.Slice:
+		if rv.IsNil() {
+			return Null
+		}
+		return Array
+	case reflect.Array:
+		return Array
+	case reflect.Map:
+		if rv.IsNil() {
+			return Null
+		}
+		return Object
+	case reflect.Struct:
+		return Object
+	}
+	return Null
+}
@@ -0,0 +1,78 @@
+package simplejson
+
+import (
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	js := mustJson(t, `{
+		"null": null,
+		"bool": false,
+		"number": 0,
+		"string": "",
+		"array": [],
+		"object": {}
+	}`)
+	js.Set("go_int", 5)
+	js.Set("go_slice", []string{"a"})
+	js.Set("go_struct", struct{ A int }{1})
+	js.Set("go_nil_map", map[string]int(nil))
+	js.Set("go_chan", make(chan int))
+
+	for key, expected := range map[string]Kind{
+		"null":       Null,
+		"bool":       Bool,
+		"number":     Number,
+		"string":     String,
+		"array":      Array,
+		"object":     Object,
+		"absent":     Missing,
+		"go_int":     Number,
+		"go_slice":   Array,
+		"go_struct":  Object,
+		"go_nil_map": Null,
+		"go_chan":    Null,
+	} {
+		if k := js.Get(key).Kind(); k != expected {
+			t.Errorf("%s: got %s", key, k)
+		}
+	}
+
+	if k := js.Get("absent").Get("deeper").Kind(); k != Missing {
+		t.Errorf("got %s", k)
+	}
+	if k := js.Get("array").GetIndex(0).Kind(); k != Missing {
+		t.Errorf("got %s", k)
+	}
+	if k := js.Kind(); k != Object {
+		t.Errorf("got %s", k)
+	}
+	if s := Kind(42).String(); s != "Kind(42)" {
+		t.Errorf("got %s", s)
+	}
+}
+
+func TestExistsIsNull(t *testing.T) {
+	js := mustJson(t, `{"a": null, "b": 1}`)
+
+	if !js.Get("a").Exists() || !js.Get("a").IsNull() {
+		t.Errorf("expected explicit null")
+	}
+	if !js.Get("b").Exists() || js.Get("b").IsNull() {
+		t.Errorf("expected a value")
+	}
+	if js.Get("c").Exists() || js.Get("c").IsNull() {
+		t.Errorf("expected missing")
+	}
+
+	missing, _ := js.CheckGet("c")
+	if missing.Exists() || missing.IsNull() || missing.Kind() != Missing {
+		t.Errorf("expected missing")
+	}
+
+	c := js.Get("c")
+	c.SetPath(nil, nil)
+	if !c.Exists() || !c.IsNull() || !js.Get("c").IsNull() {
+		t.Errorf("expected explicit null")
+	}
+}
@@ -0,0 +1,126 @@
+package simplejson
+
+import (
+	"errors"
+)
+
+var errMergePatchNull = errors.New("merge patch cannot represent an object member set to null")
+
+// MergePatch modifies `Json` by applying `patch` with RFC 7386 JSON Merge Patch
+// semantics: members set to null are deleted, objects are merged recursively
+// and any other value replaces what was there. The values stored are copies,
+// so `patch` can be modified or reused afterwards
+//
+// useful for layering overrides on top of a base document:
+//
+//	base.MergePatch(overrides)
+func (j *Json) MergePatch(patch *Json) {
+	j.unshare()
+	order := j.keyOrder()
+	data := mergePatch(order, patch.keyOrder(), j.data, patch.data)
+	order.release(j.data, data)
+	j.update(data)
+}
+
+// mergePatch applies `patch` to `target`, recording the key order that
+// `po` has for the objects of `patch` in `o` as members are added
+func mergePatch(o, po *keyOrder, target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		c := deepCopy(patch)
+		o.copyTree(po, patch, c)
+		return c
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for _, k := range po.keys(p) {
+		v := p[k]
+		if v == nil {
+			o.del(t, k)
+			continue
+		}
+		o.set(t, k, mergePatch(o, po, t[k], v))
+	}
+	return t
+}
+
+// CreateMergePatch returns the minimal RFC 7386 merge patch that
+// transforms `from` into `to`
+//
+// merge patches cannot express setting an object member to null, so
+// an error is returned if `to` requires one
+func CreateMergePatch(from, to *Json) (*Json, error) {
+	patch, changed, err := createMergePatch(from.data, to.data)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		if _, ok := from.data.(map[string]interface{}); ok {
+			return New(), nil
+		}
+		return &Json{data: deepCopy(to.data)}, nil
+	}
+	return &Json{data: deepCopy(patch)}, nil
+}
+
+func createMergePatch(from, to interface{}) (interface{}, bool, error) {
+	fm, fok := from.(map[string]interface{})
+	tm, tok := to.(map[string]interface{})
+	if !fok || !tok {
+		if valuesEqual(from, to) {
+			return nil, false, nil
+		}
+		if hasNullMember(to) {
+			return nil, false, errMergePatchNull
+		}
+		return to, true, nil
+	}
+
+	patch := make(map[string]interface{})
+	for k := range fm {
+		if _, ok := tm[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, tv := range tm {
+		fv, ok := fm[k]
+		if tv == nil {
+			if ok && fv == nil {
+				continue
+			}
+			return nil, false, errMergePatchNull
+		}
+		if !ok {
+			if hasNullMember(tv) {
+				return nil, false, errMergePatchNull
+			}
+			patch[k] = tv
+			continue
+		}
+		sub, changed, err := createMergePatch(fv, tv)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed {
+			patch[k] = sub
+		}
+	}
+	return patch, len(patch) > 0, nil
+}
+
+// hasNullMember reports whether `v` is an object containing a
+// null member at any depth, ignoring the contents of arrays
+func hasNullMember(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, mv := range m {
+		if mv == nil || hasNullMember(mv) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package simplejson
+
+import (
+	"testing"
+)
+
+func mustJson(t *testing.T, s string) *Json {
+	js, err := NewJson([]byte(s))
+	if err != nil {
+		t.Fatalf("%s: err %#v", s, err)
+	}
+	return js
+}
+
+func TestMergePatch(t *testing.T) {
+	// the examples from RFC 7386 Appendix A
+	cases := []struct {
+		target, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		js := mustJson(t, tc.target)
+		js.MergePatch(mustJson(t, tc.patch))
+		b, err := js.Encode()
+		if err != nil {
+			t.Fatalf("err %#v", err)
+		}
+		if string(b) != tc.expected {
+			t.Errorf("%s + %s: got %s expected %s", tc.target, tc.patch, b, tc.expected)
+		}
+	}
+
+	js := mustJson(t, `{"config": {"debug": false, "port": 80}}`)
+	js.Get("config").MergePatch(mustJson(t, `{"debug": true}`))
+	if b := js.GetPath("config", "debug").MustBool(); !b {
+		t.Errorf("got %#v", b)
+	}
+
+	// the document and the patch share no arrays or objects afterwards
+	js = mustJson(t, `{"a": {}}`)
+	patch := mustJson(t, `{"a": {"list": [1]}, "b": [{"c": 1}]}`)
+	js.MergePatch(patch)
+	patch.GetPath("a", "list").SetIndex(0, 2)
+	patch.Get("b").GetIndex(0).Set("c", 2)
+	js.Get("b").Append(3)
+	if b, _ := js.Encode(); string(b) != `{"a":{"list":[1]},"b":[{"c":1},3]}` {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := patch.Encode(); string(b) != `{"a":{"list":[2]},"b":[{"c":2}]}` {
+		t.Errorf("got %s", b)
+	}
+	whole := mustJson(t, `[{"c": 1}]`)
+	js.MergePatch(whole)
+	whole.GetIndex(0).Set("c", 2)
+	if b, _ := js.Encode(); string(b) != `[{"c":1}]` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	cases := []struct {
+		from, to, expected string
+	}{
+		{`{"a":"b","c":{"d":1,"e":2}}`, `{"a":"b","c":{"d":1.0,"e":3}}`, `{"c":{"e":3}}`},
+		{`{"a":"b","c":[1,2]}`, `{"c":[1,2,3],"f":{"g":true}}`, `{"a":null,"c":[1,2,3],"f":{"g":true}}`},
+		{`{"a":null}`, `{"a":null}`, `{}`},
+		{`{"a":1}`, `{"a":1}`, `{}`},
+		{`[1]`, `[1]`, `[1]`},
+		{`"x"`, `{"a":1}`, `{"a":1}`},
+	}
+	for _, tc := range cases {
+		patch, err := CreateMergePatch(mustJson(t, tc.from), mustJson(t, tc.to))
+		if err != nil {
+			t.Fatalf("err %#v", err)
+		}
+		b, err := patch.Encode()
+		if err != nil {
+			t.Fatalf("err %#v", err)
+		}
+		if string(b) != tc.expected {
+			t.Errorf("%s -> %s: got %s expected %s", tc.from, tc.to, b, tc.expected)
+		}
+
+		js := mustJson(t, tc.from)
+		js.MergePatch(patch)
+		if !valuesEqual(js.Interface(), mustJson(t, tc.to).Interface()) {
+			t.Errorf("%s -> %s: applying patch got %#v", tc.from, tc.to, js.Interface())
+		}
+	}
+
+	for _, tc := range []struct{ from, to string }{
+		{`{"a":1}`, `{"a":null}`},
+		{`{}`, `{"a":{"b":null}}`},
+		{`1`, `{"a":null}`},
+	} {
+		if _, err := CreateMergePatch(mustJson(t, tc.from), mustJson(t, tc.to)); err != errMergePatchNull {
+			t.Errorf("%s -> %s: got %#v", tc.from, tc.to, err)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonType          = reflect.TypeOf(Json{})
+)
+
+// maxNormalizeDepth bounds the nesting normalize will follow, so
+// that cyclic Go values fail instead of overflowing the stack
+const maxNormalizeDepth = 1000
+
+// FromValue returns a pointer to a new `Json` object holding `v` converted to
+// the generic tree NewJson would produce for its encoding, without encoding it
+//
+// structs (honoring `json` tags), typed maps and slices and pointers become
+// `map[string]interface{}`, `[]interface{}` and nil, and numbers become
+// `json.Number`, so that Get, Array and friends work on the result:
+//
+//	js, err := simplejson.FromValue(config)
+//	js.Get("listeners").GetIndex(0).Get("port").Int()
+func FromValue(v interface{}) (*Json, error) {
+	data, err := normalize(reflect.ValueOf(v), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Json{data: data}, nil
+}
+
+// SetNormalized modifies `Json` map by `key` and `val` like Set, but first
+// converts `val` into the generic tree as FromValue does
+//
+// unlike Set, it returns an error if `val` cannot be encoded or `Json`
+// is not an object:
+//
+//	err := js.SetNormalized("owner", User{Name: "jehiah"})
+//	js.Get("owner").Get("name").String()
+func (j *Json) SetNormalized(key string, val interface{}) error {
+	data, err := normalize(reflect.ValueOf(val), 0)
+	if err != nil {
+		return err
+	}
+	j.unshare()
+	m, err := j.Map()
+	if err != nil {
+		return err
+	}
+	j.keyOrder().set(m, key, data)
+	return nil
+}
+
+// genericValue returns `v` converted into the generic tree as FromValue
+// does if it is a Go value stored with Set, such as a `[]string`, so that
+// it compares equal to its decoded counterpart, or `v` itself otherwise
+func genericValue(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, bool, string, json.Number, map[string]interface{}, []interface{}:
+		return v
+	}
+	if n, err := normalize(reflect.ValueOf(v), 0); err == nil {
+		return n
+	}
+	return v
+}
+
+// normalize converts `rv` into decoded form following the rules of
+// json.Marshal; the result shares no maps or slices with `rv`
+func normalize(rv reflect.Value, depth int) (interface{}, error) {
+	for rv.IsValid() && rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if depth > maxNormalizeDepth {
+		return nil, &json.UnsupportedValueError{Value: rv, Str: "encountered a cycle via " + rv.Type().String()}
+	}
+
+	t := rv.Type()
+	switch {
+	case t == jsonType:
+		j := rv.Interface().(Json)
+		return normalize(reflect.ValueOf(j.data), depth+1)
+	case t == reflect.PointerTo(jsonType):
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalize(reflect.ValueOf(rv.Interface().(*Json).data), depth+1)
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() &&
+		(reflect.PointerTo(t).Implements(marshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		rv = rv.Addr()
+		t = rv.Type()
+	}
+	if t.Implements(marshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		b, err := rv.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, &json.MarshalerError{Type: t, Err: err}
+		}
+		var data interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return nil, &json.MarshalerError{Type: t, Err: err}
+		}
+		return data, nil
+	}
+	if t.Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		b, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, &json.MarshalerError{Type: t, Err: err}
+		}
+		return string(b), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		if t == numberType {
+			return json.Number(rv.String()), nil
+		}
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, &json.UnsupportedValueError{Value: rv, Str: strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+		var b []byte
+		if t.Kind() == reflect.Float32 {
+			b, _ = json.Marshal(float32(f))
+		} else {
+			b, _ = json.Marshal(f)
+		}
+		return json.Number(b), nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalize(rv.Elem(), depth+1)
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k, err := normalizeMapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			v, err := normalize(iter.Value(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			pt := reflect.PointerTo(t.Elem())
+			if !pt.Implements(marshalerType) && !pt.Implements(textMarshalerType) {
+				return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+			}
+		}
+		fallthrough
+	case reflect.Array:
+		a := make([]interface{}, rv.Len())
+		for i := range a {
+			v, err := normalize(rv.Index(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = v
+		}
+		return a, nil
+	case reflect.Struct:
+		m := make(map[string]interface{})
+	fields:
+		for _, f := range cachedTypeFields(t).list {
+			fv := rv
+			for i, x := range f.index {
+				if i > 0 && fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue fields
+					}
+					fv = fv.Elem()
+				}
+				fv = fv.Field(x)
+			}
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			v, err := normalize(fv, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if f.quoted && v != nil {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				v = string(b)
+			}
+			m[f.name] = v
+		}
+		return m, nil
+	}
+	return nil, &json.UnsupportedTypeError{Type: t}
+}
+
+// normalizeMapKey renders a map key as an object member name
+// the way json.Marshal does
+func normalizeMapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", &json.MarshalerError{Type: k.Type(), Err: err}
+		}
+		return string(b), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+// isEmptyValue reports whether `v` is omitted by the `omitempty` tag option
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type normalizeOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type normalizeConfig struct {
+	normalizeOwner
+	Ports    []int            `json:"ports"`
+	Limits   map[string]uint8 `json:"limits"`
+	Ratio    float32          `json:"ratio"`
+	Big      float64          `json:"big"`
+	Count    int              `json:"count,string"`
+	Backup   *normalizeOwner  `json:"backup"`
+	Spare    *normalizeOwner  `json:"spare,omitempty"`
+	When     time.Time        `json:"when"`
+	Data     []byte           `json:"data"`
+	ByID     map[int]string   `json:"by_id"`
+	Any      interface{}      `json:"any"`
+	Nested   *Json            `json:"nested"`
+	Hidden   string           `json:"-"`
+	internal string
+}
+
+func TestFromValue(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cfg := normalizeConfig{
+		normalizeOwner: normalizeOwner{Name: "api"},
+		Ports:          []int{80, 443},
+		Limits:         map[string]uint8{"conns": 10},
+		Ratio:          0.1,
+		Big:            1e21,
+		Count:          12,
+		Backup:         &normalizeOwner{Name: "b", Email: "b@example.com"},
+		When:           when,
+		Data:           []byte("hello"),
+		ByID:           map[int]string{7: "seven"},
+		Any:            []string{"x"},
+		Nested:         mustJson(t, `{"n": [1]}`),
+		Hidden:         "h",
+		internal:       "i",
+	}
+
+	js, err := FromValue(&cfg)
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	expected := mustJson(t, `{
+		"name": "api",
+		"ports": [80, 443],
+		"limits": {"conns": 10},
+		"ratio": 0.1,
+		"big": 1e+21,
+		"count": "12",
+		"backup": {"name": "b", "email": "b@example.com"},
+		"when": "2024-01-02T03:04:05Z",
+		"data": "aGVsbG8=",
+		"by_id": {"7": "seven"},
+		"any": ["x"],
+		"nested": {"n": [1]}
+	}`)
+	if !reflect.DeepEqual(js.Interface(), expected.Interface()) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	if p, err := js.Get("ports").GetIndex(1).Int(); err != nil || p != 443 {
+		t.Errorf("got %#v %v", p, err)
+	}
+	if n, ok := js.Get("ratio").Interface().(json.Number); !ok || n != "0.1" {
+		t.Errorf("got %#v", js.Get("ratio").Interface())
+	}
+
+	// the result shares nothing with the original value
+	js.Get("nested").Get("n").SetIndex(0, 2)
+	if cfg.Nested.Get("n").GetIndex(0).MustInt() != 1 {
+		t.Errorf("original was modified")
+	}
+
+	var back normalizeConfig
+	if err := js.Decode(&back); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if back.Count != 12 || back.Backup.Email != "b@example.com" || !back.When.Equal(when) {
+		t.Errorf("got %#v", back)
+	}
+
+	if js, err := FromValue(nil); err != nil || js.Interface() != nil {
+		t.Errorf("got %#v %v", js, err)
+	}
+}
+
+func TestFromValueErrors(t *testing.T) {
+	var unsupported *json.UnsupportedTypeError
+	if _, err := FromValue(map[string]interface{}{"c": make(chan int)}); !errors.As(err, &unsupported) {
+		t.Errorf("got %v", err)
+	}
+
+	var value *json.UnsupportedValueError
+	if _, err := FromValue([]float64{math.NaN()}); !errors.As(err, &value) {
+		t.Errorf("got %v", err)
+	}
+
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+	if _, err := FromValue(n); !errors.As(err, &value) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSetNormalized(t *testing.T) {
+	js := New()
+	if err := js.SetNormalized("owner", normalizeOwner{Name: "jehiah"}); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if s, err := js.Get("owner").Get("name").String(); err != nil || s != "jehiah" {
+		t.Errorf("got %#v %v", s, err)
+	}
+	if _, ok := js.Get("owner").CheckGet("email"); ok {
+		t.Errorf("expected email to be omitted")
+	}
+
+	if err := js.SetNormalized("ids", []uint16{1, 2}); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if a, err := js.Get("ids").IntArray(); err != nil || !reflect.DeepEqual(a, []int{1, 2}) {
+		t.Errorf("got %#v %v", a, err)
+	}
+
+	if err := js.SetNormalized("bad", func() {}); err == nil {
+		t.Errorf("expected error")
+	}
+	if err := js.Get("ids").SetNormalized("x", 1); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// NewOrdered returns a pointer to a new, empty `Json` object that
+// encodes its keys in the order they were added
+func NewOrdered() *Json {
+	j := New()
+	j.order = newKeyOrder()
+	return j
+}
+
+// NewOrderedJson returns a pointer to a new `Json` object after unmarshaling
+// `body` bytes like NewJson, but remembering the order of every object's keys
+//
+// Encode and EncodePretty write keys in that order, with keys added by Set
+// or SetPath after the existing ones, so a document can be edited and written
+// back without reordering it:
+//
+//	js, err := simplejson.NewOrderedJson(config)
+//	js.SetPath([]string{"image", "tag"}, "v1.2.3")
+//	out, err := js.EncodePretty()
+//
+// the key order is kept alongside the ordinary maps returned by Map, MustMap
+// and Interface. Keys added to those maps directly are written after the
+// known keys in ascending order, as are the keys of new objects stored by
+// Transform. The key order of a value removed from
+// the document (by Del, DelPath, or by Set replacing it) is forgotten, so
+// it is also written in ascending order if added back.
+func NewOrderedJson(body []byte) (*Json, error) {
+	return NewOrderedFromReader(bytes.NewReader(body))
+}
+
+// NewOrderedFromReader returns a *Json by decoding from an io.Reader
+// like NewFromReader, but remembering the order of every object's keys
+func NewOrderedFromReader(r io.Reader) (*Json, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	order := newKeyOrder()
+	data, err := order.decode(dec)
+	if err != nil {
+		return nil, err
+	}
+	return &Json{data: data, order: order}, nil
+}
+
+// keyOrder remembers the key order of the objects in an ordered
+// document, identifying each object by the address of its map
+type keyOrder struct {
+	objects map[uintptr]*objectKeys
+}
+
+// objectKeys is the key order of one object
+type objectKeys struct {
+	m     map[string]interface{} // keeps the map, and so its address, alive
+	keys  []string               // in order, including keys since deleted
+	index map[string]int         // the position in keys of each current key
+}
+
+func newKeyOrder() *keyOrder {
+	return &keyOrder{objects: make(map[uintptr]*objectKeys)}
+}
+
+// keyOrder returns the key order of the document `j` belongs to,
+// or nil if it is not ordered
+func (j *Json) keyOrder() *keyOrder {
+	for ; j != nil; j = j.parent {
+		if j.order != nil {
+			return j.order
+		}
+	}
+	return nil
+}
+
+func mapID(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+// entry returns the key order of `m`, starting one from its
+// current keys in ascending order if `create` is set
+func (o *keyOrder) entry(m map[string]interface{}, create bool) *objectKeys {
+	if o == nil {
+		return nil
+	}
+	e, ok := o.objects[mapID(m)]
+	if !ok && create {
+		e = &objectKeys{m: m, index: make(map[string]int, len(m))}
+		for _, k := range sortedKeys(m) {
+			e.add(k)
+		}
+		o.objects[mapID(m)] = e
+	}
+	return e
+}
+
+// keys returns the keys of `m` in order, followed by any
+// keys it has gained without being recorded in ascending order
+func (o *keyOrder) keys(m map[string]interface{}) []string {
+	e := o.entry(m, false)
+	if e == nil {
+		return sortedKeys(m)
+	}
+	keys := make([]string, 0, len(m))
+	for i, k := range e.keys {
+		if _, ok := m[k]; ok && e.index[k] == i {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) < len(m) {
+		var extra []string
+		for k := range m {
+			if _, ok := e.index[k]; !ok {
+				extra = append(extra, k)
+			}
+		}
+		sort.Strings(extra)
+		keys = append(keys, extra...)
+	}
+	return keys
+}
+
+// set stores `val` under `key` in `m`, recording `key` as the last
+// key of `m` if it is new and releasing the value it replaces
+func (o *keyOrder) set(m map[string]interface{}, key string, val interface{}) {
+	if old, ok := m[key]; ok {
+		o.release(old, val)
+	} else if e := o.entry(m, true); e != nil {
+		e.add(key)
+	}
+	m[key] = val
+}
+
+// del removes `key` from `m` and from its key order, dropping
+// the key order of the objects in the value removed
+func (o *keyOrder) del(m map[string]interface{}, key string) {
+	old, ok := m[key]
+	if !ok {
+		return
+	}
+	delete(m, key)
+	if e := o.entry(m, false); e != nil {
+		e.del(key)
+	}
+	o.forget(old)
+}
+
+// release drops the key order of the objects in `old`, a value that has
+// been replaced by `val`, other than those that are also part of `val`
+func (o *keyOrder) release(old, val interface{}) {
+	if o == nil {
+		return
+	}
+	if id, ok := containerID(old); ok {
+		if vid, _ := containerID(val); vid == id {
+			return
+		}
+	}
+	switch old.(type) {
+	case map[string]interface{}, []interface{}:
+		keep := o.subtree(val)
+		o.forget(old)
+		o.adopt(nil, keep)
+	}
+}
+
+// subtree returns the key order of the objects in `v`
+func (o *keyOrder) subtree(v interface{}) *keyOrder {
+	c := newKeyOrder()
+	c.copyTree(o, v, v)
+	return c
+}
+
+func (e *objectKeys) add(key string) {
+	if _, ok := e.index[key]; ok {
+		return
+	}
+	e.index[key] = len(e.keys)
+	e.keys = append(e.keys, key)
+}
+
+func (e *objectKeys) del(key string) {
+	delete(e.index, key)
+	// compact once deleted keys make up most of the list
+	if len(e.keys) > 8 && len(e.keys) > 2*len(e.index) {
+		keys := make([]string, 0, len(e.index))
+		for i, k := range e.keys {
+			if e.index[k] == i {
+				e.index[k] = len(keys)
+				keys = append(keys, k)
+			}
+		}
+		e.keys = keys
+	}
+}
+
+// copyTree records in `o` the key order that `src` has for the objects of
+// `from` against the corresponding objects of `to`, a deep copy of `from`
+func (o *keyOrder) copyTree(src *keyOrder, from, to interface{}) {
+	if o == nil {
+		return
+	}
+	switch x := from.(type) {
+	case map[string]interface{}:
+		y, ok := to.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if src.entry(x, false) != nil {
+			e := &objectKeys{m: y, index: make(map[string]int, len(y))}
+			for _, k := range src.keys(x) {
+				e.add(k)
+			}
+			o.objects[mapID(y)] = e
+		}
+		for k, v := range x {
+			o.copyTree(src, v, y[k])
+		}
+	case []interface{}:
+		y, ok := to.([]interface{})
+		if !ok {
+			return
+		}
+		for i := 0; i < len(x) && i < len(y); i++ {
+			o.copyTree(src, x[i], y[i])
+		}
+	}
+}
+
+// forget drops the key order of every object in `v`
+func (o *keyOrder) forget(v interface{}) {
+	if o == nil {
+		return
+	}
+	switch x := v.(type) {
+	case map[string]interface{}:
+		delete(o.objects, mapID(x))
+		for _, mv := range x {
+			o.forget(mv)
+		}
+	case []interface{}:
+		for _, av := range x {
+			o.forget(av)
+		}
+	}
+}
+
+// replace moves the key order of the objects in `to`, a copy of `from`
+// that is taking its place in the document, over from those in `from`
+func (o *keyOrder) replace(from, to interface{}) {
+	if o == nil {
+		return
+	}
+	copied := newKeyOrder()
+	copied.copyTree(o, from, to)
+	o.adopt(from, copied)
+}
+
+// adopt replaces the key order of the objects in `from` with
+// that of `c`, which describes the data taking its place
+func (o *keyOrder) adopt(from interface{}, c *keyOrder) {
+	if o == nil {
+		return
+	}
+	o.forget(from)
+	for id, e := range c.objects {
+		o.objects[id] = e
+	}
+}
+
+// clone returns a key order for a LazyClone of the document, which
+// shares its objects until one of the two documents copies them
+func (o *keyOrder) clone() *keyOrder {
+	if o == nil {
+		return nil
+	}
+	c := newKeyOrder()
+	for id, e := range o.objects {
+		c.objects[id] = e
+	}
+	return c
+}
+
+// decode reads the next JSON value from `dec` as Decode would,
+// recording the key order of each object
+func (o *keyOrder) decode(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]interface{})
+		e := o.entry(m, true)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			val, err := o.decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			e.add(key)
+			m[key] = val
+		}
+		_, err = dec.Token()
+		return m, err
+	case json.Delim('['):
+		a := []interface{}{}
+		for dec.More() {
+			val, err := o.decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, val)
+		}
+		_, err = dec.Token()
+		return a, err
+	}
+	return tok, nil
+}
+
+// marshal encodes `v` as json.Marshal would, but writing
+// object keys in order
+func (o *keyOrder) marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := o.encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (o *keyOrder) encode(buf *bytes.Buffer, v interface{}) error {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, k := range o.keys(x) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			buf.WriteByte(':')
+			if err := o.encode(buf, x[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, av := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := o.encode(buf, av); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
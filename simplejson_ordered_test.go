@@ -0,0 +1,226 @@
+package simplejson
+
+import (
+	"testing"
+)
+
+func mustOrdered(t *testing.T, s string) *Json {
+	t.Helper()
+	js, err := NewOrderedJson([]byte(s))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	return js
+}
+
+func TestOrderedRoundTrip(t *testing.T) {
+	in := `{"z":1,"a":{"y":[{"q":true,"b":null}],"c":"x"},"m":2.50}`
+	js := mustOrdered(t, in)
+	if b, _ := js.Encode(); string(b) != in {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := js.Get("a").Encode(); string(b) != `{"y":[{"q":true,"b":null}],"c":"x"}` {
+		t.Errorf("got %s", b)
+	}
+
+	b, err := js.EncodePretty()
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	want := `{
+  "z": 1,
+  "a": {
+    "y": [
+      {
+        "q": true,
+        "b": null
+      }
+    ],
+    "c": "x"
+  },
+  "m": 2.50
+}`
+	if string(b) != want {
+		t.Errorf("got %s", b)
+	}
+
+	// duplicate keys keep their first position and their last value
+	js = mustOrdered(t, `{"b":1,"a":2,"b":3}`)
+	if b, _ := js.Encode(); string(b) != `{"b":3,"a":2}` {
+		t.Errorf("got %s", b)
+	}
+
+	if _, err := NewOrderedJson([]byte(`{"a":`)); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestOrderedEdits(t *testing.T) {
+	js := mustOrdered(t, `{"name":"app","image":{"repo":"x","tag":"v1"},"env":[]}`)
+	js.Set("name", "app2")
+	js.Set("replicas", 3)
+	js.Get("image").Set("pullPolicy", "Always")
+	js.SetPath([]string{"image", "tag"}, "v2")
+	js.SetPath([]string{"labels", "tier"}, "web")
+	js.Del("env")
+	if b, _ := js.Encode(); string(b) != `{"name":"app2","image":{"repo":"x","tag":"v2","pullPolicy":"Always"},"replicas":3,"labels":{"tier":"web"}}` {
+		t.Errorf("got %s", b)
+	}
+
+	// a deleted key that is set again moves to the end
+	js.Set("env", nil)
+	js.Del("name")
+	js.Set("name", "app3")
+	if b, _ := js.Encode(); string(b) != `{"image":{"repo":"x","tag":"v2","pullPolicy":"Always"},"replicas":3,"labels":{"tier":"web"},"env":null,"name":"app3"}` {
+		t.Errorf("got %s", b)
+	}
+
+	// keys added to the map directly come last, in ascending order
+	m := js.Get("labels").MustMap()
+	m["b"] = 1
+	m["a"] = 2
+	if b, _ := js.Get("labels").Encode(); string(b) != `{"tier":"web","a":2,"b":1}` {
+		t.Errorf("got %s", b)
+	}
+
+	var keys []string
+	for k := range js.Entries() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 5 || keys[0] != "image" || keys[4] != "name" {
+		t.Errorf("got %#v", keys)
+	}
+
+	js = NewOrdered()
+	js.Set("b", 1)
+	js.Set("a", 2)
+	if b, _ := js.Encode(); string(b) != `{"b":1,"a":2}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestOrderedClone(t *testing.T) {
+	js := mustOrdered(t, `{"b":{"y":1,"x":2},"a":[{"d":1,"c":2}]}`)
+
+	c := js.Clone()
+	c.Get("b").Set("w", 3)
+	if b, _ := c.Encode(); string(b) != `{"b":{"y":1,"x":2,"w":3},"a":[{"d":1,"c":2}]}` {
+		t.Errorf("got %s", b)
+	}
+
+	lc := js.LazyClone()
+	lc.Get("a").GetIndex(0).Set("e", 3)
+	js.Get("b").Del("y")
+	js.Get("b").Set("y", 4)
+	if b, _ := lc.Encode(); string(b) != `{"b":{"y":1,"x":2},"a":[{"d":1,"c":2,"e":3}]}` {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := js.Encode(); string(b) != `{"b":{"x":2,"y":4},"a":[{"d":1,"c":2}]}` {
+		t.Errorf("got %s", b)
+	}
+
+	err := js.ApplyPatch(mustJson(t, `[{"op":"add","path":"/b/v","value":5},{"op":"remove","path":"/a/0/d"}]`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if b, _ := js.Encode(); string(b) != `{"b":{"x":2,"y":4,"v":5},"a":[{"c":2}]}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestOrderedRemoval(t *testing.T) {
+	js := mustOrdered(t, `{"a":1,"b":{"d":1,"c":2},"e":[{"g":1,"f":2}]}`)
+	for i := 0; i < 1000; i++ {
+		js.Set("x", map[string]interface{}{"n": map[string]interface{}{}})
+		js.Get("x").Set("n", map[string]interface{}{})
+		js.Del("x")
+	}
+	if n := len(js.order.objects); n != 3 {
+		t.Errorf("got %d objects", n)
+	}
+
+	// every way of deleting a key moves it to the end when set again
+	js.DelPath("a")
+	js.Set("a", 9)
+	js.DelPointer("/b/d")
+	js.Get("b").Set("d", 9)
+	if b, _ := js.Encode(); string(b) != `{"b":{"c":2,"d":9},"e":[{"g":1,"f":2}],"a":9}` {
+		t.Errorf("got %s", b)
+	}
+
+	js.Get("e").RemoveIndex(0)
+	js.DelPath("b")
+	if n := len(js.order.objects); n != 1 {
+		t.Errorf("got %d objects", n)
+	}
+
+	// moving a value with a patch keeps its order
+	js = mustOrdered(t, `{"a":{"z":1,"y":2},"b":{}}`)
+	err := js.ApplyPatch(mustJson(t, `[{"op":"move","from":"/a","path":"/b/a"}]`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if b, _ := js.Encode(); string(b) != `{"b":{"a":{"z":1,"y":2}}}` {
+		t.Errorf("got %s", b)
+	}
+	if n := len(js.order.objects); n != 3 {
+		t.Errorf("got %d objects", n)
+	}
+}
+
+func TestOrderedViews(t *testing.T) {
+	js := mustOrdered(t, `{"items":[{"y":1,"b":2,"c":3}],"a":0}`)
+	if k := js.Keys(); len(k) != 2 || k[0] != "items" || k[1] != "a" {
+		t.Errorf("got %#v", k)
+	}
+	if k := js.Get("items").GetIndex(0).Keys(); len(k) != 3 || k[0] != "y" {
+		t.Errorf("got %#v", k)
+	}
+
+	res, err := js.Query("$.items[0]")
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if b, _ := res[0].Encode(); string(b) != `{"y":1,"b":2,"c":3}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestOrderedWrites(t *testing.T) {
+	js := NewOrdered()
+	js.Set("z", 1)
+	js.SetBranch([]interface{}{"b"}, 1)
+	js.Set("a", 1)
+	js.MergePatch(mustOrdered(t, `{"y":1,"c":{"q":1,"p":2}}`))
+	if b, _ := js.Encode(); string(b) != `{"z":1,"b":1,"a":1,"y":1,"c":{"q":1,"p":2}}` {
+		t.Errorf("got %s", b)
+	}
+
+	for i := 0; i < 1000; i++ {
+		js.SetBranch([]interface{}{"x"}, map[string]interface{}{"n": map[string]interface{}{}})
+		js.SetBranch([]interface{}{"x", "n"}, map[string]interface{}{})
+		js.MergePatch(mustJson(t, `{"c":{"q":{"r":{}}}}`))
+		js.MergePatch(mustJson(t, `{"c":{"q":1}}`))
+	}
+	if n := len(js.order.objects); n != 2 {
+		t.Errorf("got %d objects", n)
+	}
+
+	js.RenameKeys(func(k string) string { return k })
+	js.FilterKeys(func(k string) bool { return k != "y" })
+	var visited []string
+	js.Walk(func(path Path, node *Json) WalkAction {
+		visited = append(visited, path.Pointer())
+		return WalkContinue
+	})
+	if b, _ := js.Encode(); string(b) != `{"z":1,"b":1,"a":1,"c":{"q":1,"p":2},"x":{"n":{}}}` {
+		t.Errorf("got %s", b)
+	}
+	if len(visited) != 9 || visited[1] != "/z" || visited[4] != "/c" || visited[5] != "/c/q" {
+		t.Errorf("got %#v", visited)
+	}
+	// renaming records the order of every non-empty object
+	if n := len(js.order.objects); n != 3 {
+		t.Errorf("got %d objects", n)
+	}
+}
@@ -0,0 +1,178 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errPatchNotArray   = errors.New("patch must be an array of operations")
+	errPatchOp         = errors.New("operation must be an object with a string \"op\"")
+	errPatchUnknownOp  = errors.New("unknown operation")
+	errPatchMember     = errors.New("missing or invalid member")
+	errPatchTestFailed = errors.New("test failed")
+	errPatchMoveInto   = errors.New("cannot move a value into one of its own children")
+)
+
+// PatchError records the RFC 6902 JSON Patch operation that failed
+type PatchError struct {
+	Index int    // position of the operation in the patch, -1 if the patch itself is invalid
+	Op    string // the operation name, if one could be read
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("json patch: %s", e.Err)
+	}
+	return fmt.Sprintf("json patch operation %d (%s): %s", e.Index, e.Op, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch modifies `Json` by applying the RFC 6902 JSON Patch `ops`, an array
+// of add, remove, replace, move, copy and test operations addressed by JSON Pointer
+//
+// the patch is atomic: if any operation fails, including a `test`, a `*PatchError`
+// identifying it is returned and `Json` is left untouched:
+//
+//	ops, _ := simplejson.NewJson([]byte(`[
+//		{"op": "test", "path": "/version", "value": 3},
+//		{"op": "replace", "path": "/version", "value": 4}
+//	]`))
+//	err := js.ApplyPatch(ops)
+func (j *Json) ApplyPatch(ops *Json) error {
+	j.unshare()
+	a, err := ops.Array()
+	if err != nil {
+		return &PatchError{Index: -1, Err: errPatchNotArray}
+	}
+
+	doc := &Json{data: deepCopy(j.data)}
+	order := j.keyOrder()
+	if order != nil {
+		doc.order = newKeyOrder()
+		doc.order.copyTree(order, j.data, doc.data)
+	}
+	for i, v := range a {
+		op, ok := v.(map[string]interface{})
+		if !ok {
+			return &PatchError{Index: i, Err: errPatchOp}
+		}
+		name, ok := op["op"].(string)
+		if !ok {
+			return &PatchError{Index: i, Err: errPatchOp}
+		}
+		if err := doc.applyPatchOp(name, op); err != nil {
+			return &PatchError{Index: i, Op: name, Err: err}
+		}
+	}
+	order.adopt(j.data, doc.order.subtree(doc.data))
+	return j.update(doc.data)
+}
+
+func (j *Json) applyPatchOp(name string, op map[string]interface{}) error {
+	path, ok := op["path"].(string)
+	if !ok {
+		return fmt.Errorf("%w \"path\"", errPatchMember)
+	}
+
+	switch name {
+	case "add", "replace", "test":
+		val, ok := op["value"]
+		if !ok {
+			return fmt.Errorf("%w \"value\"", errPatchMember)
+		}
+		switch name {
+		case "add":
+			return j.patchAdd(path, deepCopy(val))
+		case "replace":
+			return j.patchReplace(path, deepCopy(val))
+		}
+		curr, err := j.GetPointer(path)
+		if err != nil {
+			return err
+		}
+		if !valuesEqual(curr.data, val) {
+			return errPatchTestFailed
+		}
+		return nil
+	case "remove":
+		return j.DelPointer(path)
+	case "move", "copy":
+		from, ok := op["from"].(string)
+		if !ok {
+			return fmt.Errorf("%w \"from\"", errPatchMember)
+		}
+		src, err := j.GetPointer(from)
+		if err != nil {
+			return err
+		}
+		if name == "copy" {
+			return j.patchAdd(path, deepCopy(src.data))
+		}
+		if path == from {
+			return nil
+		}
+		if strings.HasPrefix(path, from+"/") {
+			return errPatchMoveInto
+		}
+		// removing the value forgets its key order, which it keeps
+		moved := j.keyOrder().subtree(src.data)
+		if err := j.DelPointer(from); err != nil {
+			return err
+		}
+		j.keyOrder().adopt(nil, moved)
+		return j.patchAdd(path, src.data)
+	}
+	return errPatchUnknownOp
+}
+
+// patchAdd inserts into arrays rather than replacing, as the
+// RFC 6902 "add" operation requires
+func (j *Json) patchAdd(ptr string, val interface{}) error {
+	order := j.keyOrder()
+	return j.applyPointer(ptr, val, func(container interface{}, tok string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			order.set(v, tok, val)
+			return v, nil
+		case []interface{}:
+			idx, err := pointerArrayIndex(tok, v, true)
+			if err != nil {
+				return nil, err
+			}
+			n := make([]interface{}, 0, len(v)+1)
+			n = append(n, v[:idx]...)
+			n = append(n, val)
+			return append(n, v[idx:]...), nil
+		}
+		return nil, errPointerNotContainer
+	})
+}
+
+// patchReplace requires the target location to exist
+func (j *Json) patchReplace(ptr string, val interface{}) error {
+	order := j.keyOrder()
+	return j.applyPointer(ptr, val, func(container interface{}, tok string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			if _, ok := v[tok]; !ok {
+				return nil, errPointerNotFound
+			}
+			order.set(v, tok, val)
+			return v, nil
+		case []interface{}:
+			idx, err := pointerArrayIndex(tok, v, false)
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = val
+			return v, nil
+		}
+		return nil, errPointerNotContainer
+	})
+}
@@ -0,0 +1,73 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	// mostly taken from RFC 6902 Appendix A
+	cases := []struct {
+		doc, patch, expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{`{"/":1,"~":2}`, `[{"op":"move","from":"/~1","path":"/~0"}]`, `{"~":1}`},
+	}
+	for _, tc := range cases {
+		js := mustJson(t, tc.doc)
+		if err := js.ApplyPatch(mustJson(t, tc.patch)); err != nil {
+			t.Fatalf("%s: err %#v", tc.patch, err)
+		}
+		b, err := js.Encode()
+		if err != nil {
+			t.Fatalf("err %#v", err)
+		}
+		if string(b) != tc.expected {
+			t.Errorf("%s: got %s expected %s", tc.patch, b, tc.expected)
+		}
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	cases := []struct {
+		patch string
+		index int
+		err   error
+	}{
+		{`{"op":"add"}`, -1, errPatchNotArray},
+		{`[{"path":"/a"}]`, 0, errPatchOp},
+		{`[{"op":"frobnicate","path":"/a"}]`, 0, errPatchUnknownOp},
+		{`[{"op":"add","value":1}]`, 0, errPatchMember},
+		{`[{"op":"add","path":"/x"}]`, 0, errPatchMember},
+		{`[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":3}]`, 1, errPatchTestFailed},
+		{`[{"op":"remove","path":"/a"},{"op":"remove","path":"/missing"}]`, 1, errPointerNotFound},
+		{`[{"op":"add","path":"/list/3","value":1}]`, 0, errPointerOutOfRange},
+		{`[{"op":"replace","path":"/list/2","value":1}]`, 0, errPointerOutOfRange},
+		{`[{"op":"add","path":"/missing/a","value":1}]`, 0, errPointerNotFound},
+		{`[{"op":"move","from":"/obj","path":"/obj/inner"}]`, 0, errPatchMoveInto},
+		{`[{"op":"copy","from":"/nope","path":"/a"}]`, 0, errPointerNotFound},
+	}
+	for _, tc := range cases {
+		js := mustJson(t, `{"a":1,"list":[1,2],"obj":{}}`)
+		err := js.ApplyPatch(mustJson(t, tc.patch))
+		var pe *PatchError
+		if !errors.As(err, &pe) || pe.Index != tc.index || !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %v", tc.patch, err)
+		}
+		if b, _ := js.Encode(); string(b) != `{"a":1,"list":[1,2],"obj":{}}` {
+			t.Errorf("%s: document modified to %s", tc.patch, b)
+		}
+	}
+}
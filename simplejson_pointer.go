@@ -0,0 +1,289 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	errPointerSyntax       = errors.New("pointer must be empty or start with '/'")
+	errPointerNotFound     = fmt.Errorf("key %w", ErrNotFound)
+	errPointerIndex        = errors.New("invalid array index")
+	errPointerOutOfRange   = fmt.Errorf("%w: array index out of range", ErrNotFound)
+	errPointerNotContainer = fmt.Errorf("%w: value is not an object or array", ErrTypeMismatch)
+	errPointerRoot         = errors.New("cannot delete the document root")
+	errPointerNotObject    = fmt.Errorf("%w: value is not an object", ErrTypeMismatch)
+	errPointerNotArray     = fmt.Errorf("%w: value is not an array", ErrTypeMismatch)
+	errPointerSegment      = errors.New("path segment must be a string or int")
+	errBranchPadding       = fmt.Errorf("array index more than %d past the end", maxBranchPadding)
+)
+
+// maxBranchPadding bounds the nulls SetBranch and AppendPath pad an
+// array with, so that a huge index fails instead of exhausting memory
+const maxBranchPadding = 1 << 16
+
+// PointerError records a failed RFC 6901 JSON Pointer operation
+// and the reference token that could not be resolved
+type PointerError struct {
+	Pointer string // the full pointer as supplied
+	Token   string // the unescaped reference token that failed
+	Index   int    // position of Token in the pointer, -1 if the pointer itself is invalid
+	Err     error
+}
+
+func (e *PointerError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("json pointer %q: %s", e.Pointer, e.Err)
+	}
+	return fmt.Sprintf("json pointer %q: segment %d (%q): %s", e.Pointer, e.Index, e.Token, e.Err)
+}
+
+func (e *PointerError) Unwrap() error {
+	return e.Err
+}
+
+// parsePointer splits `ptr` into its unescaped reference tokens
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, &PointerError{Pointer: ptr, Index: -1, Err: errPointerSyntax}
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		if strings.IndexByte(t, '~') >= 0 {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+		}
+	}
+	return tokens, nil
+}
+
+// escapePointerToken escapes `~` and `/` so that `s` can be used as
+// a single reference token
+func escapePointerToken(s string) string {
+	if strings.IndexAny(s, "~/") < 0 {
+		return s
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// branchPointer renders a branch of string keys and int indices
+// as a JSON Pointer
+func branchPointer(branch []interface{}) string {
+	var b strings.Builder
+	for _, seg := range branch {
+		b.WriteByte('/')
+		switch s := seg.(type) {
+		case string:
+			b.WriteString(escapePointerToken(s))
+		default:
+			fmt.Fprint(&b, s)
+		}
+	}
+	return b.String()
+}
+
+// pointerIndex parses an array index token, rejecting leading zeros
+// and signs as RFC 6901 requires
+func pointerIndex(tok string) (int, bool) {
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(tok); i++ {
+		if tok[i] < '0' || tok[i] > '9' {
+			return 0, false
+		}
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// GetPointer returns a pointer to a new `Json` object for the value
+// referenced by the RFC 6901 JSON Pointer `ptr`, or `Json` itself for ""
+//
+// unlike GetPath, a pointer can descend into arrays. Like the result of Get,
+// the returned `Json` remembers its location, so it can be modified in place:
+//
+//	js.GetPointer("/items/3/name")
+func (j *Json) GetPointer(ptr string) (*Json, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	curr := j
+	for i, tok := range tokens {
+		switch v := curr.data.(type) {
+		case map[string]interface{}:
+			val, ok := v[tok]
+			if !ok {
+				return nil, &PointerError{ptr, tok, i, errPointerNotFound}
+			}
+			curr = &Json{data: val, parent: curr, parentGen: curr.gen, key: tok}
+		case []interface{}:
+			idx, err := pointerArrayIndex(tok, v, false)
+			if err != nil {
+				return nil, &PointerError{ptr, tok, i, err}
+			}
+			curr = &Json{data: v[idx], parent: curr, parentGen: curr.gen, key: idx}
+		default:
+			return nil, &PointerError{ptr, tok, i, errPointerNotContainer}
+		}
+	}
+	return curr, nil
+}
+
+// SetPointer modifies `Json` by writing `val` at the location referenced
+// by the RFC 6901 JSON Pointer `ptr`
+//
+// every container above the final segment must already exist. When the
+// final segment addresses an array, an existing index is replaced and
+// either `len(array)` or `-` appends:
+//
+//	js.SetPointer("/items/-", map[string]interface{}{"name": "new"})
+func (j *Json) SetPointer(ptr string, val interface{}) error {
+	order := j.keyOrder()
+	return j.applyPointer(ptr, val, func(container interface{}, tok string) (interface{}, error) {
+		switch v := container.(type) {
+		case map[string]interface{}:
+			order.set(v, tok, val)
+			return v, nil
+		case []interface{}:
+			idx, err := pointerArrayIndex(tok, v, true)
+			if err != nil {
+				return nil, err
+			}
+			if idx == len(v) {
+				return append(v, val), nil
+			}
+			v[idx] = val
+			return v, nil
+		}
+		return nil, errPointerNotContainer
+	})
+}
+
+// DelPointer modifies `Json` by removing the value referenced by the
+// RFC 6901 JSON Pointer `ptr`
+//
+// array elements are spliced out, shifting any following elements down
+func (j *Json) DelPointer(ptr string) error {
+	j.unshare()
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return &PointerError{Pointer: ptr, Index: -1, Err: errPointerRoot}
+	}
+	order := j.keyOrder()
+	data, err := pointerApply(j.data, tokens, 0, func(container interface{}, tok string) (interface{}, error) {
+		return pointerRemove(order, container, tok)
+	})
+	if err != nil {
+		err.(*PointerError).Pointer = ptr
+		return err
+	}
+	return j.update(data)
+}
+
+// applyPointer resolves `ptr` and stores the result of `fn` applied to the
+// container holding its final token, or `root` if `ptr` is empty
+func (j *Json) applyPointer(ptr string, root interface{}, fn func(interface{}, string) (interface{}, error)) error {
+	j.unshare()
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return j.update(root)
+	}
+	data, err := pointerApply(j.data, tokens, 0, fn)
+	if err != nil {
+		err.(*PointerError).Pointer = ptr
+		return err
+	}
+	return j.update(data)
+}
+
+// pointerApply walks tokens[i:len-1] from `node` and replaces the container
+// holding the final token with the result of `fn`; containers are only
+// modified once `fn` has succeeded, and grown or shrunk arrays are returned
+// so the caller can store them back in their parent
+func pointerApply(node interface{}, tokens []string, i int, fn func(interface{}, string) (interface{}, error)) (interface{}, error) {
+	tok := tokens[i]
+	if i == len(tokens)-1 {
+		n, err := fn(node, tok)
+		if err != nil {
+			return nil, &PointerError{Token: tok, Index: i, Err: err}
+		}
+		return n, nil
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		child, ok := v[tok]
+		if !ok {
+			return nil, &PointerError{Token: tok, Index: i, Err: errPointerNotFound}
+		}
+		n, err := pointerApply(child, tokens, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		v[tok] = n
+		return v, nil
+	case []interface{}:
+		idx, err := pointerArrayIndex(tok, v, false)
+		if err != nil {
+			return nil, &PointerError{Token: tok, Index: i, Err: err}
+		}
+		n, err := pointerApply(v[idx], tokens, i+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		v[idx] = n
+		return v, nil
+	}
+	return nil, &PointerError{Token: tok, Index: i, Err: errPointerNotContainer}
+}
+
+// pointerArrayIndex resolves `tok` against `a`, also allowing `-` and
+// `len(a)` to address the position past the end when `end` is set
+func pointerArrayIndex(tok string, a []interface{}, end bool) (int, error) {
+	if end && tok == "-" {
+		return len(a), nil
+	}
+	idx, ok := pointerIndex(tok)
+	if !ok {
+		return 0, errPointerIndex
+	}
+	if idx > len(a) || (idx == len(a) && !end) {
+		return 0, errPointerOutOfRange
+	}
+	return idx, nil
+}
+
+// pointerRemove deletes `tok` from `container`, splicing arrays
+func pointerRemove(order *keyOrder, container interface{}, tok string) (interface{}, error) {
+	switch v := container.(type) {
+	case map[string]interface{}:
+		if _, ok := v[tok]; !ok {
+			return nil, errPointerNotFound
+		}
+		order.del(v, tok)
+		return v, nil
+	case []interface{}:
+		idx, err := pointerArrayIndex(tok, v, false)
+		if err != nil {
+			return nil, err
+		}
+		order.forget(v[idx])
+		return append(v[:idx:idx], v[idx+1:]...), nil
+	}
+	return nil, errPointerNotContainer
+}
@@ -0,0 +1,157 @@
+package simplejson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetPointer(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"items": [{"name": "a"}, {"name": "b"}],
+		"a/b": 1,
+		"m~n": 2,
+		"": 3
+	}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	cases := []struct {
+		ptr      string
+		expected interface{}
+	}{
+		{"/items/1/name", "b"},
+		{"/a~1b", int64(1)},
+		{"/m~0n", int64(2)},
+		{"/", int64(3)},
+	}
+	for _, tc := range cases {
+		v, err := js.GetPointer(tc.ptr)
+		if err != nil {
+			t.Fatalf("%s: err %#v", tc.ptr, err)
+		}
+		var got interface{}
+		if _, ok := tc.expected.(string); ok {
+			got = v.MustString()
+		} else {
+			got = v.MustInt64()
+		}
+		if got != tc.expected {
+			t.Errorf("%s: got %#v expected %#v", tc.ptr, got, tc.expected)
+		}
+	}
+
+	if v, err := js.GetPointer(""); err != nil || v.Interface() == nil {
+		t.Errorf("got %#v %#v", v, err)
+	}
+
+	errCases := []struct {
+		ptr   string
+		index int
+		err   error
+	}{
+		{"items", -1, errPointerSyntax},
+		{"/missing/0", 0, errPointerNotFound},
+		{"/items/2", 1, errPointerOutOfRange},
+		{"/items/01", 1, errPointerIndex},
+		{"/items/-", 1, errPointerIndex},
+		{"/items/0/name/x", 3, errPointerNotContainer},
+	}
+	for _, tc := range errCases {
+		_, err := js.GetPointer(tc.ptr)
+		var pe *PointerError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%s: got %#v", tc.ptr, err)
+		}
+		if pe.Index != tc.index || pe.Err != tc.err {
+			t.Errorf("%s: got %#v", tc.ptr, pe)
+		}
+	}
+}
+
+func TestGetPointerMutation(t *testing.T) {
+	js := mustJson(t, `{"a": [1], "b": [{"c": 1}]}`)
+	p, _ := js.GetPointer("/a")
+	if err := p.Append(2); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	p, _ = js.GetPointer("/b/0")
+	p.Set("d", 2)
+	if b, _ := js.Encode(); string(b) != `{"a":[1,2],"b":[{"c":1,"d":2}]}` {
+		t.Errorf("got %s", b)
+	}
+
+	c := js.LazyClone()
+	p, _ = c.GetPointer("/b/0")
+	p.Set("e", 3)
+	if b, _ := js.Encode(); string(b) != `{"a":[1,2],"b":[{"c":1,"d":2}]}` {
+		t.Errorf("original modified: %s", b)
+	}
+	if b, _ := c.Encode(); string(b) != `{"a":[1,2],"b":[{"c":1,"d":2,"e":3}]}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestSetPointer(t *testing.T) {
+	js, err := NewJson([]byte(`{"items": [{"name": "a"}], "obj": {}}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	for _, ptr := range []string{"/items/0/name", "/items/-", "/items/2", "/obj/new"} {
+		if err := js.SetPointer(ptr, "x"); err != nil {
+			t.Fatalf("%s: err %#v", ptr, err)
+		}
+	}
+
+	expected := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"name": "x"}, "x", "x"},
+		"obj":   map[string]interface{}{"new": "x"},
+	}
+	if !reflect.DeepEqual(js.Interface(), expected) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	err = js.SetPointer("/missing/key", "x")
+	if pe, ok := err.(*PointerError); !ok || pe.Token != "missing" {
+		t.Errorf("got %#v", err)
+	}
+	err = js.SetPointer("/items/5", "x")
+	if pe, ok := err.(*PointerError); !ok || pe.Err != errPointerOutOfRange {
+		t.Errorf("got %#v", err)
+	}
+
+	if err := js.SetPointer("", "root"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if s := js.MustString(); s != "root" {
+		t.Errorf("got %#v", s)
+	}
+}
+
+func TestDelPointer(t *testing.T) {
+	js, err := NewJson([]byte(`{"items": [1, 2, 3], "obj": {"a": 1, "b": 2}}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	for _, ptr := range []string{"/items/1", "/obj/a"} {
+		if err := js.DelPointer(ptr); err != nil {
+			t.Fatalf("%s: err %#v", ptr, err)
+		}
+	}
+
+	if a := js.Get("items").MustArray(); len(a) != 2 {
+		t.Errorf("got %#v", a)
+	}
+	if _, ok := js.Get("obj").CheckGet("a"); ok {
+		t.Errorf("got %#v expected false", ok)
+	}
+
+	for _, ptr := range []string{"", "/obj/a", "/items/2"} {
+		if err := js.DelPointer(ptr); err == nil {
+			t.Errorf("%s: expected error", ptr)
+		}
+	}
+}
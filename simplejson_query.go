@@ -0,0 +1,1246 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONPath is a compiled RFC 9535 JSONPath query
+//
+// a JSONPath is safe for concurrent use and can be evaluated
+// against any number of documents
+type JSONPath struct {
+	expr     string
+	segments []pathSegment
+}
+
+// JSONPathError describes a syntax error in a JSONPath expression
+type JSONPathError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *JSONPathError) Error() string {
+	return fmt.Sprintf("jsonpath %q: offset %d: %s", e.Expr, e.Offset, e.Msg)
+}
+
+// CompileJSONPath parses a JSONPath expression such as
+// `$..book[?@.price < 10].title` so it can be reused across documents
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := &pathParser{expr: expr}
+	segments, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return &JSONPath{expr: expr, segments: segments}, nil
+}
+
+// MustCompileJSONPath is like CompileJSONPath but panics if
+// the expression cannot be parsed
+func MustCompileJSONPath(expr string) *JSONPath {
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source expression
+func (p *JSONPath) String() string {
+	return p.expr
+}
+
+// Query returns a `Json` object for every node selected by the
+// compiled expression, in document order
+//
+// object members are visited in key order so results are deterministic.
+// Like the result of Get, each `Json` remembers its location, so it can be
+// modified in place; the root node is `j` itself
+func (p *JSONPath) Query(j *Json) []*Json {
+	root := &queryNode{value: j.data}
+	nodes := evalSegments(j.data, root, p.segments)
+	located := map[*queryNode]*Json{root: j}
+	ret := make([]*Json, 0, len(nodes))
+	for _, n := range nodes {
+		ret = append(ret, n.json(located))
+	}
+	return ret
+}
+
+// Query evaluates the RFC 9535 JSONPath expression `expr` and returns
+// a `Json` object for every selected node
+//
+// useful for pulling values out of deeply nested documents:
+//
+//	prices, err := js.Query("$.store..price")
+//	cheap, err := js.Query("$..book[?@.price < 10]")
+func (j *Json) Query(expr string) ([]*Json, error) {
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Query(j), nil
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selector interface {
+	apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode
+}
+
+// queryNode is a value selected while evaluating a query, along
+// with the object key or array index it was found at
+type queryNode struct {
+	value  interface{}
+	parent *queryNode
+	key    interface{}
+}
+
+func (n *queryNode) child(key, value interface{}) *queryNode {
+	return &queryNode{value: value, parent: n, key: key}
+}
+
+// json returns the `Json` for `n`, linked to those of its parents
+// the way Get links them, reusing the ones in `located`
+func (n *queryNode) json(located map[*queryNode]*Json) *Json {
+	if j, ok := located[n]; ok {
+		return j
+	}
+	parent := n.parent.json(located)
+	j := &Json{data: n.value, parent: parent, parentGen: parent.gen, key: n.key}
+	located[n] = j
+	return j
+}
+
+// values returns the values of `nodes`
+func values(nodes []*queryNode) []interface{} {
+	ret := make([]interface{}, 0, len(nodes))
+	for _, n := range nodes {
+		ret = append(ret, n.value)
+	}
+	return ret
+}
+
+func evalSegments(root interface{}, node *queryNode, segments []pathSegment) []*queryNode {
+	nodes := []*queryNode{node}
+	for _, seg := range segments {
+		var next []*queryNode
+		for _, n := range nodes {
+			if seg.descendant {
+				next = descend(root, n, seg.selectors, next)
+				continue
+			}
+			for _, s := range seg.selectors {
+				next = s.apply(root, n, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descend applies selectors to `node` and then to each of its
+// descendants, parents before children
+func descend(root interface{}, node *queryNode, selectors []selector, out []*queryNode) []*queryNode {
+	for _, s := range selectors {
+		out = s.apply(root, node, out)
+	}
+	for _, c := range children(node) {
+		out = descend(root, c, selectors, out)
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// children returns the members of an object in key order or
+// the elements of an array
+func children(node *queryNode) []*queryNode {
+	var ret []*queryNode
+	switch v := node.value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			ret = append(ret, node.child(k, v[k]))
+		}
+	case []interface{}:
+		for i, e := range v {
+			ret = append(ret, node.child(i, e))
+		}
+	}
+	return ret
+}
+
+type nameSelector string
+
+func (s nameSelector) apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode {
+	if m, ok := node.value.(map[string]interface{}); ok {
+		if v, ok := m[string(s)]; ok {
+			out = append(out, node.child(string(s), v))
+		}
+	}
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode {
+	return append(out, children(node)...)
+}
+
+type indexSelector int
+
+func (s indexSelector) apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode {
+	if a, ok := node.value.([]interface{}); ok {
+		i := int(s)
+		if i < 0 {
+			i += len(a)
+		}
+		if i >= 0 && i < len(a) {
+			out = append(out, node.child(i, a[i]))
+		}
+	}
+	return out
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode {
+	a, ok := node.value.([]interface{})
+	if !ok || s.step == 0 {
+		return out
+	}
+	n := len(a)
+	normalize := func(i int) int {
+		if i < 0 {
+			return n + i
+		}
+		return i
+	}
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+
+	if s.step > 0 {
+		start, end := 0, n
+		if s.start != nil {
+			start = normalize(*s.start)
+		}
+		if s.end != nil {
+			end = normalize(*s.end)
+		}
+		for i := clamp(start, 0, n); i < clamp(end, 0, n); i += s.step {
+			out = append(out, node.child(i, a[i]))
+		}
+		return out
+	}
+
+	start, end := n-1, -n-1
+	if s.start != nil {
+		start = *s.start
+	}
+	if s.end != nil {
+		end = *s.end
+	}
+	lower := clamp(normalize(end), -1, n-1)
+	for i := clamp(normalize(start), -1, n-1); lower < i; i += s.step {
+		out = append(out, node.child(i, a[i]))
+	}
+	return out
+}
+
+type filterSelector struct {
+	expr logicalExpr
+}
+
+func (s filterSelector) apply(root interface{}, node *queryNode, out []*queryNode) []*queryNode {
+	for _, c := range children(node) {
+		if s.expr.test(root, c.value) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// logicalExpr is a filter expression evaluating to LogicalTrue or LogicalFalse
+type logicalExpr interface {
+	test(root, curr interface{}) bool
+}
+
+type orExpr []logicalExpr
+
+func (e orExpr) test(root, curr interface{}) bool {
+	for _, x := range e {
+		if x.test(root, curr) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (e andExpr) test(root, curr interface{}) bool {
+	for _, x := range e {
+		if !x.test(root, curr) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (e notExpr) test(root, curr interface{}) bool {
+	return !e.expr.test(root, curr)
+}
+
+type existsExpr struct {
+	query *filterQuery
+}
+
+func (e existsExpr) test(root, curr interface{}) bool {
+	return len(e.query.nodes(root, curr)) > 0
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right filterOperand
+}
+
+func (e comparisonExpr) test(root, curr interface{}) bool {
+	l, lok := e.left.value(root, curr)
+	r, rok := e.right.value(root, curr)
+
+	switch e.op {
+	case "==":
+		return compareEqual(l, lok, r, rok)
+	case "!=":
+		return !compareEqual(l, lok, r, rok)
+	case "<":
+		return compareLess(l, lok, r, rok)
+	case "<=":
+		return compareLess(l, lok, r, rok) || compareEqual(l, lok, r, rok)
+	case ">":
+		return compareLess(r, rok, l, lok)
+	case ">=":
+		return compareLess(r, rok, l, lok) || compareEqual(l, lok, r, rok)
+	}
+	return false
+}
+
+func compareEqual(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+	return valuesEqual(l, r)
+}
+
+func compareLess(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return false
+	}
+	if c, ok := compareNumbers(l, r); ok {
+		return c < 0
+	}
+	ls, ok := l.(string)
+	if !ok {
+		return false
+	}
+	rs, ok := r.(string)
+	return ok && ls < rs
+}
+
+// number is a numeric value of any supported representation
+// normalized for comparison
+type number struct {
+	kind byte // 'i', 'u' or 'f'
+	i    int64
+	u    uint64
+	f    float64
+}
+
+func toNumber(v interface{}) (number, bool) {
+	switch v.(type) {
+	case json.Number:
+		s := v.(json.Number).String()
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return number{kind: 'i', i: i}, true
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return number{kind: 'u', u: u}, true
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return number{}, false
+		}
+		return number{kind: 'f', f: f}, true
+	case float32, float64:
+		return number{kind: 'f', f: reflect.ValueOf(v).Float()}, true
+	case int, int8, int16, int32, int64:
+		return number{kind: 'i', i: reflect.ValueOf(v).Int()}, true
+	case uint, uint8, uint16, uint32, uint64:
+		return number{kind: 'u', u: reflect.ValueOf(v).Uint()}, true
+	}
+	return number{}, false
+}
+
+func (n number) isNaN() bool {
+	return n.kind == 'f' && math.IsNaN(n.f)
+}
+
+func (n number) float() float64 {
+	switch n.kind {
+	case 'i':
+		return float64(n.i)
+	case 'u':
+		return float64(n.u)
+	}
+	return n.f
+}
+
+// compareNumbers orders two numeric values regardless of whether they are
+// held as `json.Number` or as Go numeric types, returning false if either
+// is not a number or is NaN, which is neither ordered nor equal to any other
+func compareNumbers(a, b interface{}) (int, bool) {
+	x, ok := toNumber(a)
+	if !ok || x.isNaN() {
+		return 0, false
+	}
+	y, ok := toNumber(b)
+	if !ok || y.isNaN() {
+		return 0, false
+	}
+
+	switch {
+	case x.kind == 'i' && y.kind == 'i':
+		return cmpOrdered(x.i < y.i, x.i > y.i), true
+	case x.kind == 'u' && y.kind == 'u':
+		return cmpOrdered(x.u < y.u, x.u > y.u), true
+	case x.kind == 'i' && y.kind == 'u':
+		if x.i < 0 {
+			return -1, true
+		}
+		return cmpOrdered(uint64(x.i) < y.u, uint64(x.i) > y.u), true
+	case x.kind == 'u' && y.kind == 'i':
+		if y.i < 0 {
+			return 1, true
+		}
+		return cmpOrdered(x.u < uint64(y.i), x.u > uint64(y.i)), true
+	}
+	fx, fy := x.float(), y.float()
+	return cmpOrdered(fx < fy, fx > fy), true
+}
+
+func cmpOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// filterOperand produces a single value, or false for Nothing
+type filterOperand interface {
+	value(root, curr interface{}) (interface{}, bool)
+}
+
+type literal struct {
+	v interface{}
+}
+
+func (l literal) value(root, curr interface{}) (interface{}, bool) {
+	return l.v, true
+}
+
+type filterQuery struct {
+	relative bool
+	segments []pathSegment
+}
+
+func (q *filterQuery) nodes(root, curr interface{}) []interface{} {
+	if q.relative {
+		return values(evalSegments(root, &queryNode{value: curr}, q.segments))
+	}
+	return values(evalSegments(root, &queryNode{value: root}, q.segments))
+}
+
+func (q *filterQuery) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		switch seg.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (q *filterQuery) value(root, curr interface{}) (interface{}, bool) {
+	nodes := q.nodes(root, curr)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0], true
+}
+
+type funcType int
+
+const (
+	valueType funcType = iota
+	logicalType
+	nodesType
+)
+
+// funcArg holds exactly one of its fields depending on the
+// declared parameter type
+type funcArg struct {
+	value filterOperand
+	nodes *filterQuery
+}
+
+type funcCall struct {
+	name string
+	args []funcArg
+	re   *regexp.Regexp // precompiled match/search pattern given as a literal
+}
+
+var funcSignatures = map[string]struct {
+	params []funcType
+	result funcType
+}{
+	"length": {[]funcType{valueType}, valueType},
+	"count":  {[]funcType{nodesType}, valueType},
+	"match":  {[]funcType{valueType, valueType}, logicalType},
+	"search": {[]funcType{valueType, valueType}, logicalType},
+	"value":  {[]funcType{nodesType}, valueType},
+}
+
+func (f *funcCall) value(root, curr interface{}) (interface{}, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.args[0].value.value(root, curr)
+		if !ok {
+			return nil, false
+		}
+		switch x := v.(type) {
+		case string:
+			return utf8.RuneCountInString(x), true
+		case []interface{}:
+			return len(x), true
+		case map[string]interface{}:
+			return len(x), true
+		}
+		return nil, false
+	case "count":
+		return len(f.args[0].nodes.nodes(root, curr)), true
+	case "value":
+		return f.args[0].nodes.value(root, curr)
+	}
+	return nil, false
+}
+
+func (f *funcCall) test(root, curr interface{}) bool {
+	v, ok := f.args[0].value.value(root, curr)
+	if !ok {
+		return false
+	}
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	re := f.re
+	if re == nil {
+		p, ok := f.args[1].value.value(root, curr)
+		if !ok {
+			return false
+		}
+		pattern, ok := p.(string)
+		if !ok {
+			return false
+		}
+		var err error
+		re, err = compileIRegexp(pattern, f.name == "match")
+		if err != nil {
+			return false
+		}
+	}
+	return re.MatchString(s)
+}
+
+// compileIRegexp translates an RFC 9485 I-Regexp into Go syntax, where
+// the only difference that matters is that `.` must not match `\r`
+func compileIRegexp(pattern string, full bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if full {
+		b.WriteString(`^(?:`)
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteByte(c)
+			i++
+			c = pattern[i]
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '.' && !inClass:
+			b.WriteString(`[^\n\r]`)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	if full {
+		b.WriteString(`)$`)
+	}
+	return regexp.Compile(b.String())
+}
+
+// pathParser is a recursive descent parser for the RFC 9535 grammar
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return &JSONPathError{Expr: p.expr, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *pathParser) eof() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *pathParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.expr[p.pos]
+}
+
+func (p *pathParser) skipSpace() {
+	for !p.eof() {
+		switch p.expr[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *pathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) expect(s string) error {
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func (p *pathParser) parseQuery() ([]pathSegment, error) {
+	if err := p.expect("$"); err != nil {
+		return nil, err
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return segments, nil
+}
+
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+	for {
+		start := p.pos
+		p.skipSpace()
+		if p.peek() != '.' && p.peek() != '[' {
+			p.pos = start
+			return segments, nil
+		}
+		seg, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+}
+
+func (p *pathParser) parseSegment() (pathSegment, error) {
+	var seg pathSegment
+	if p.consume("..") {
+		seg.descendant = true
+		if p.peek() == '[' {
+			sels, err := p.parseBracketed()
+			seg.selectors = sels
+			return seg, err
+		}
+	} else if !p.consume(".") {
+		sels, err := p.parseBracketed()
+		seg.selectors = sels
+		return seg, err
+	}
+
+	if p.consume("*") {
+		seg.selectors = []selector{wildcardSelector{}}
+		return seg, nil
+	}
+	name := p.parseMemberName()
+	if name == "" {
+		return seg, p.errorf("expected member name or '*'")
+	}
+	seg.selectors = []selector{nameSelector(name)}
+	return seg, nil
+}
+
+func (p *pathParser) parseMemberName() string {
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		isFirst := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80
+		if !isFirst && !(p.pos > start && r >= '0' && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+	return p.expr[start:p.pos]
+}
+
+func (p *pathParser) parseBracketed() ([]selector, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var sels []selector
+	for {
+		p.skipSpace()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return sels, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *pathParser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return nameSelector(s), err
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		expr, err := p.parseLogicalOr()
+		return filterSelector{expr}, err
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	}
+	return nil, p.errorf("invalid selector")
+}
+
+func (p *pathParser) parseIndexOrSlice() (selector, error) {
+	var s sliceSelector
+	if p.peek() != ':' {
+		i, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ':' {
+			return indexSelector(i), nil
+		}
+		s.start = &i
+	}
+
+	p.pos++ // ':'
+	p.skipSpace()
+	if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+		i, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		s.end = &i
+		p.skipSpace()
+	}
+
+	s.step = 1
+	if p.consume(":") {
+		p.skipSpace()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			s.step = i
+		}
+	}
+	return s, nil
+}
+
+// maxSafeInt bounds indices to the I-JSON interoperable integer range
+const maxSafeInt = 1<<53 - 1
+
+func (p *pathParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	s := p.expr[start:p.pos]
+	if p.pos == digits || (p.expr[digits] == '0' && (p.pos-digits > 1 || digits > start)) {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	i, err := strconv.ParseInt(s, 10, strconv.IntSize)
+	if err != nil || i > maxSafeInt || i < -maxSafeInt {
+		p.pos = start
+		return 0, p.errorf("integer out of range")
+	}
+	return int(i), nil
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.expr[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c < 0x20:
+			return "", p.errorf("control character in string")
+		case c != '\\':
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		p.pos++
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c = p.expr[p.pos]
+		p.pos++
+		switch c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '/', '\\':
+			b.WriteByte(c)
+		case 'u':
+			r, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		default:
+			if c != quote {
+				p.pos--
+				return "", p.errorf("invalid escape")
+			}
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *pathParser) parseHex4() (rune, error) {
+	if p.pos+4 > len(p.expr) {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	v, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	p.pos += 4
+	return rune(v), nil
+}
+
+func (p *pathParser) parseUnicodeEscape() (rune, error) {
+	r, err := p.parseHex4()
+	if err != nil {
+		return 0, err
+	}
+	if r >= 0xDC00 && r <= 0xDFFF {
+		return 0, p.errorf("unpaired low surrogate")
+	}
+	if r < 0xD800 || r > 0xDBFF {
+		return r, nil
+	}
+	if !p.consume(`\u`) {
+		return 0, p.errorf("unpaired high surrogate")
+	}
+	lo, err := p.parseHex4()
+	if err != nil {
+		return 0, err
+	}
+	if lo < 0xDC00 || lo > 0xDFFF {
+		return 0, p.errorf("invalid low surrogate")
+	}
+	return utf16.DecodeRune(r, lo), nil
+}
+
+func (p *pathParser) parseLogicalOr() (logicalExpr, error) {
+	var exprs orExpr
+	for {
+		e, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		start := p.pos
+		p.skipSpace()
+		if !p.consume("||") {
+			p.pos = start
+			break
+		}
+		p.skipSpace()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *pathParser) parseLogicalAnd() (logicalExpr, error) {
+	var exprs andExpr
+	for {
+		e, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		start := p.pos
+		p.skipSpace()
+		if !p.consume("&&") {
+			p.pos = start
+			break
+		}
+		p.skipSpace()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *pathParser) parseBasic() (logicalExpr, error) {
+	if p.consume("!") {
+		p.skipSpace()
+		if p.peek() == '(' {
+			e, err := p.parseParen()
+			return notExpr{e}, err
+		}
+		e, err := p.parseTest()
+		return notExpr{e}, err
+	}
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	op := p.parseComparisonOp()
+	if op == "" {
+		p.pos = start
+		return p.parseTest()
+	}
+	if err := p.checkOperand(left, start); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	rightStart := p.pos
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkOperand(right, rightStart); err != nil {
+		return nil, err
+	}
+	return comparisonExpr{op, left, right}, nil
+}
+
+func (p *pathParser) parseComparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// checkOperand enforces that queries in comparisons are singular and
+// functions in comparisons return values
+func (p *pathParser) checkOperand(c filterOperand, pos int) error {
+	switch v := c.(type) {
+	case *filterQuery:
+		if !v.singular() {
+			p.pos = pos
+			return p.errorf("non-singular query in comparison")
+		}
+	case *funcCall:
+		if funcSignatures[v.name].result != valueType {
+			p.pos = pos
+			return p.errorf("function %s() does not return a value", v.name)
+		}
+	}
+	return nil
+}
+
+func (p *pathParser) parseParen() (logicalExpr, error) {
+	p.pos++ // '('
+	p.skipSpace()
+	e, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// parseTest parses an existence test or a logical function call
+func (p *pathParser) parseTest() (logicalExpr, error) {
+	start := p.pos
+	c, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch v := c.(type) {
+	case *filterQuery:
+		return existsExpr{v}, nil
+	case *funcCall:
+		if funcSignatures[v.name].result == logicalType {
+			return v, nil
+		}
+		p.pos = start
+		return nil, p.errorf("function %s() result must be compared", v.name)
+	}
+	p.pos = start
+	return nil, p.errorf("literal must be compared")
+}
+
+func (p *pathParser) parseOperand() (filterOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &filterQuery{relative: c == '@', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return literal{s}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case c >= 'a' && c <= 'z':
+		start := p.pos
+		for c := p.peek(); (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_'; c = p.peek() {
+			p.pos++
+		}
+		name := p.expr[start:p.pos]
+		if p.peek() == '(' {
+			p.pos = start
+			return p.parseFunction()
+		}
+		switch name {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("unknown literal %q", name)
+	}
+	return nil, p.errorf("expected filter expression")
+}
+
+func (p *pathParser) parseNumber() (filterOperand, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	if p.pos == digits || (p.expr[digits] == '0' && p.pos-digits > 1) {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	if p.consume(".") {
+		frac := p.pos
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			p.pos++
+		}
+		if p.pos == frac {
+			return nil, p.errorf("invalid number")
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		exp := p.pos
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			p.pos++
+		}
+		if p.pos == exp {
+			return nil, p.errorf("invalid number")
+		}
+	}
+	f, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+	if err != nil || math.IsInf(f, 0) {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	return literal{f}, nil
+}
+
+func (p *pathParser) parseFunction() (filterOperand, error) {
+	start := p.pos
+	for p.peek() != '(' {
+		p.pos++
+	}
+	name := p.expr[start:p.pos]
+	sig, ok := funcSignatures[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	p.pos++ // '('
+
+	f := &funcCall{name: name}
+	for i := range sig.params {
+		p.skipSpace()
+		if i > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+		}
+		arg, err := p.parseFuncArg(sig.params[i])
+		if err != nil {
+			return nil, err
+		}
+		f.args = append(f.args, arg)
+	}
+	p.skipSpace()
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if name == "match" || name == "search" {
+		if l, ok := f.args[1].value.(literal); ok {
+			if pattern, ok := l.v.(string); ok {
+				re, err := compileIRegexp(pattern, name == "match")
+				if err != nil {
+					p.pos = start
+					return nil, p.errorf("invalid regular expression: %s", err)
+				}
+				f.re = re
+			}
+		}
+	}
+	return f, nil
+}
+
+func (p *pathParser) parseFuncArg(t funcType) (funcArg, error) {
+	start := p.pos
+	if t == nodesType {
+		c, err := p.parseOperand()
+		if err != nil {
+			return funcArg{}, err
+		}
+		q, ok := c.(*filterQuery)
+		if !ok {
+			p.pos = start
+			return funcArg{}, p.errorf("expected query argument")
+		}
+		return funcArg{nodes: q}, nil
+	}
+
+	c, err := p.parseOperand()
+	if err != nil {
+		return funcArg{}, err
+	}
+	if err := p.checkOperand(c, start); err != nil {
+		return funcArg{}, err
+	}
+	return funcArg{value: c}, nil
+}
@@ -0,0 +1,163 @@
+package simplejson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const storeJSON = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	}
+}`
+
+func queryStrings(t *testing.T, js *Json, expr string) []string {
+	res, err := js.Query(expr)
+	if err != nil {
+		t.Fatalf("%s: err %#v", expr, err)
+	}
+	ret := make([]string, 0, len(res))
+	for _, r := range res {
+		b, err := r.Encode()
+		if err != nil {
+			t.Fatalf("%s: err %#v", expr, err)
+		}
+		ret = append(ret, string(b))
+	}
+	return ret
+}
+
+func TestQuery(t *testing.T) {
+	js, err := NewJson([]byte(storeJSON))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	cases := []struct {
+		expr     string
+		expected []string
+	}{
+		{`$.store.book[*].author`, []string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}},
+		{`$..author`, []string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}},
+		{`$.store..price`, []string{`399`, `8.95`, `12.99`, `8.99`, `22.99`}},
+		{`$..book[2].title`, []string{`"Moby Dick"`}},
+		{`$..book[-1].title`, []string{`"The Lord of the Rings"`}},
+		{`$..book[0,1].title`, []string{`"Sayings of the Century"`, `"Sword of Honour"`}},
+		{`$..book[:2].title`, []string{`"Sayings of the Century"`, `"Sword of Honour"`}},
+		{`$..book[::-2].title`, []string{`"The Lord of the Rings"`, `"Sword of Honour"`}},
+		{`$..book[?@.isbn].title`, []string{`"Moby Dick"`, `"The Lord of the Rings"`}},
+		{`$..book[?@.price < 10].title`, []string{`"Sayings of the Century"`, `"Moby Dick"`}},
+		{`$..book[?@.price<10 && @.category=='fiction'].title`, []string{`"Moby Dick"`}},
+		{`$..book[?!(@.price >= 10) || @.author == "Evelyn Waugh"].price`, []string{`8.95`, `12.99`, `8.99`}},
+		{`$..book[?@.price > $.store.bicycle.price]`, []string{}},
+		{`$..book[?length(@.title) == 9].author`, []string{`"Herman Melville"`}},
+		{`$..book[?match(@.author, 'J.*')].price`, []string{`22.99`}},
+		{`$..book[?search(@.title, 'of')].price`, []string{`8.95`, `12.99`, `22.99`}},
+		{`$.store[?count(@.*) == 2].color`, []string{`"red"`}},
+		{`$.store['bicycle']["color"]`, []string{`"red"`}},
+		{`$.missing`, []string{}},
+	}
+	for _, tc := range cases {
+		got := queryStrings(t, js, tc.expr)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: got %#v expected %#v", tc.expr, got, tc.expected)
+		}
+	}
+}
+
+func TestQueryComparisons(t *testing.T) {
+	js, err := NewJson([]byte(`{"a": [1, 1.0, "1", null, true, [1], {"x": 1}]}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	js.Get("a").MustArray()[0] = 1
+
+	cases := []struct {
+		expr     string
+		expected []string
+	}{
+		{`$.a[?@ == 1]`, []string{`1`, `1.0`}},
+		{`$.a[?@ == null]`, []string{`null`}},
+		{`$.a[?@.x == 1]`, []string{`{"x":1}`}},
+		{`$.a[?@.x == @.y]`, []string{`1`, `1.0`, `"1"`, `null`, `true`, `[1]`}},
+		{`$.a[?@ < "2"]`, []string{`"1"`}},
+	}
+	for _, tc := range cases {
+		got := queryStrings(t, js, tc.expr)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: got %#v expected %#v", tc.expr, got, tc.expected)
+		}
+	}
+
+	// NaN is neither equal to nor ordered against any number
+	js.Get("a").Append(math.NaN())
+	for _, expr := range []string{`$.a[?@ == 1]`, `$.a[?@ <= 1]`, `$.a[?@ >= 1]`} {
+		if got := queryStrings(t, js, expr); !reflect.DeepEqual(got, []string{`1`, `1.0`}) {
+			t.Errorf("%s: got %#v", expr, got)
+		}
+	}
+}
+
+func TestQueryMutation(t *testing.T) {
+	js := mustJson(t, `{"a": {"x": 1}, "b": [[1], [2]]}`)
+	c := js.LazyClone()
+
+	res, err := c.Query("$.a")
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	res[0].Set("y", 2)
+	res, _ = c.Query("$.b[?@[0] > 1]")
+	if err := res[0].Append(3); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if b, _ := c.Encode(); string(b) != `{"a":{"x":1,"y":2},"b":[[1],[2,3]]}` {
+		t.Errorf("got %s", b)
+	}
+	if b, _ := js.Encode(); string(b) != `{"a":{"x":1},"b":[[1],[2]]}` {
+		t.Errorf("original modified: %s", b)
+	}
+}
+
+func TestCompileJSONPathErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`store`,
+		`$.`,
+		`$[01]`,
+		`$[-0]`,
+		`$['unterminated]`,
+		`$[?@.a == 'x' &&]`,
+		`$[?@..a == 1]`,
+		`$[?@.* == 1]`,
+		`$[?length(@.a)]`,
+		`$[?count(1) == 1]`,
+		`$[?nope(@)]`,
+		`$[?1]`,
+		`$[?@ == [1]]`,
+		`$.a `,
+	} {
+		_, err := CompileJSONPath(expr)
+		if _, ok := err.(*JSONPathError); !ok {
+			t.Errorf("%q: got %#v", expr, err)
+		}
+	}
+
+	p := MustCompileJSONPath(`$..price`)
+	for _, doc := range []string{`{"price": 1}`, `[{"price": 2}, {"price": 3}]`} {
+		js, err := NewJson([]byte(doc))
+		if err != nil {
+			t.Fatalf("err %#v", err)
+		}
+		if res := p.Query(js); len(res) == 0 {
+			t.Errorf("%s: got %#v", doc, res)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // Implements the json.Unmarshaler interface.
@@ -29,7 +32,11 @@ func NewFromReader(r io.Reader) (*Json, error) {
 func (j *Json) Float64() (float64, error) {
 	switch j.data.(type) {
 	case json.Number:
-		return j.data.(json.Number).Float64()
+		f, err := j.data.(json.Number).Float64()
+		if err != nil {
+			return f, j.valueError("number", err)
+		}
+		return f, nil
 	case float32, float64:
 		return reflect.ValueOf(j.data).Float(), nil
 	case int, int8, int16, int32, int64:
@@ -37,7 +44,7 @@ func (j *Json) Float64() (float64, error) {
 	case uint, uint8, uint16, uint32, uint64:
 		return float64(reflect.ValueOf(j.data).Uint()), nil
 	}
-	return 0, errors.New("invalid value type")
+	return 0, j.valueError("number", ErrTypeMismatch)
 }
 
 // Int coerces into an int
@@ -45,7 +52,10 @@ func (j *Json) Int() (int, error) {
 	switch j.data.(type) {
 	case json.Number:
 		i, err := j.data.(json.Number).Int64()
-		return int(i), err
+		if err != nil {
+			return int(i), j.valueError("number", err)
+		}
+		return int(i), nil
 	case float32, float64:
 		return int(reflect.ValueOf(j.data).Float()), nil
 	case int, int8, int16, int32, int64:
@@ -53,14 +63,18 @@ func (j *Json) Int() (int, error) {
 	case uint, uint8, uint16, uint32, uint64:
 		return int(reflect.ValueOf(j.data).Uint()), nil
 	}
-	return 0, errors.New("invalid value type")
+	return 0, j.valueError("number", ErrTypeMismatch)
 }
 
 // Int64 coerces into an int64
 func (j *Json) Int64() (int64, error) {
 	switch j.data.(type) {
 	case json.Number:
-		return j.data.(json.Number).Int64()
+		i, err := j.data.(json.Number).Int64()
+		if err != nil {
+			return i, j.valueError("number", err)
+		}
+		return i, nil
 	case float32, float64:
 		return int64(reflect.ValueOf(j.data).Float()), nil
 	case int, int8, int16, int32, int64:
@@ -68,14 +82,18 @@ func (j *Json) Int64() (int64, error) {
 	case uint, uint8, uint16, uint32, uint64:
 		return int64(reflect.ValueOf(j.data).Uint()), nil
 	}
-	return 0, errors.New("invalid value type")
+	return 0, j.valueError("number", ErrTypeMismatch)
 }
 
 // Uint64 coerces into an uint64
 func (j *Json) Uint64() (uint64, error) {
 	switch j.data.(type) {
 	case json.Number:
-		return strconv.ParseUint(j.data.(json.Number).String(), 10, 64)
+		u, err := strconv.ParseUint(j.data.(json.Number).String(), 10, 64)
+		if err != nil {
+			return u, j.valueError("number", err)
+		}
+		return u, nil
 	case float32, float64:
 		return uint64(reflect.ValueOf(j.data).Float()), nil
 	case int, int8, int16, int32, int64:
@@ -83,5 +101,266 @@ func (j *Json) Uint64() (uint64, error) {
 	case uint, uint8, uint16, uint32, uint64:
 		return reflect.ValueOf(j.data).Uint(), nil
 	}
-	return 0, errors.New("invalid value type")
+	return 0, j.valueError("number", ErrTypeMismatch)
+}
+
+var (
+	// ErrFractional is returned by the strict integer accessors for
+	// numbers that have a fractional part
+	ErrFractional = errors.New("number has a fractional part")
+	// ErrNegative is returned when a negative number is converted
+	// to an unsigned type
+	ErrNegative = errors.New("negative number cannot be unsigned")
+	// ErrOutOfRange is returned when a number does not fit in the
+	// requested type
+	ErrOutOfRange = errors.New("number out of range")
+)
+
+// IntStrict coerces into an int like Int, but returns ErrFractional
+// rather than truncating and ErrOutOfRange rather than wrapping,
+// taking the platform size of int into account
+func (j *Json) IntStrict() (int, error) {
+	i, err := coerceInt64(j.data, true)
+	if err != nil {
+		return 0, j.valueError("number", err)
+	}
+	if i < math.MinInt || i > math.MaxInt {
+		return 0, j.valueError("number", fmt.Errorf("%w: %d does not fit in int", ErrOutOfRange, i))
+	}
+	return int(i), nil
+}
+
+// Int64Strict coerces into an int64 like Int64, but returns ErrFractional
+// rather than truncating and ErrOutOfRange rather than wrapping
+func (j *Json) Int64Strict() (int64, error) {
+	i, err := coerceInt64(j.data, true)
+	if err != nil {
+		return 0, j.valueError("number", err)
+	}
+	return i, nil
+}
+
+// Uint64Strict coerces into an uint64 like Uint64, but returns ErrFractional
+// rather than truncating, ErrNegative for values below zero and
+// ErrOutOfRange rather than wrapping
+func (j *Json) Uint64Strict() (uint64, error) {
+	u, err := coerceUint64(j.data, true)
+	if err != nil {
+		return 0, j.valueError("number", err)
+	}
+	return u, nil
+}
+
+// IntLoose coerces into an int like Int, additionally
+// accepting a string holding a number such as "42"
+func (j *Json) IntLoose() (int, error) {
+	return j.looseNumber().Int()
+}
+
+// Int64Loose coerces into an int64 like Int64, additionally
+// accepting a string holding a number such as "42"
+func (j *Json) Int64Loose() (int64, error) {
+	return j.looseNumber().Int64()
+}
+
+// Uint64Loose coerces into an uint64 like Uint64, additionally
+// accepting a string holding a number such as "42"
+func (j *Json) Uint64Loose() (uint64, error) {
+	return j.looseNumber().Uint64()
+}
+
+// Float64Loose coerces into a float64 like Float64, additionally
+// accepting a string holding a number such as "3.5"
+func (j *Json) Float64Loose() (float64, error) {
+	return j.looseNumber().Float64()
+}
+
+// BoolLoose type asserts to `bool` like Bool, additionally
+// accepting the strings "true" and "false"
+func (j *Json) BoolLoose() (bool, error) {
+	switch j.data {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return j.Bool()
+}
+
+// StringLoose type asserts to `string` like String, additionally
+// rendering numbers and bools the way Encode would, e.g. 42 as "42"
+func (j *Json) StringLoose() (string, error) {
+	switch j.data.(type) {
+	case string:
+		return j.data.(string), nil
+	case json.Number:
+		return j.data.(json.Number).String(), nil
+	case bool:
+		return strconv.FormatBool(j.data.(bool)), nil
+	}
+	if _, ok := toNumber(j.data); ok {
+		if b, err := json.Marshal(j.data); err == nil {
+			return string(b), nil
+		}
+	}
+	return "", j.valueError("string", ErrTypeMismatch)
+}
+
+// looseNumber returns `j`, or a read-only `Json` at the same location
+// holding a numeric string as a `json.Number` so the regular accessors
+// can parse it
+//
+// only strings in JSON number syntax qualify: "42", "-3.5" and "1e3"
+// do, while " 42", "+1", "0x10" and "NaN" do not
+func (j *Json) looseNumber() *Json {
+	if s, ok := j.data.(string); ok && isJSONNumber(s) {
+		return &Json{data: json.Number(s), parent: j.parent, key: j.key}
+	}
+	return j
+}
+
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	if last := s[len(s)-1]; last < '0' || last > '9' {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// coerceInt64 is Int64 with range checking; fractions are truncated
+// unless `strict` is set
+func coerceInt64(v interface{}, strict bool) (int64, error) {
+	switch v.(type) {
+	case json.Number:
+		s, err := integerDigits(v.(json.Number).String(), !strict)
+		if err != nil {
+			return 0, err
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("%w: %s does not fit in int64", ErrOutOfRange, v)
+		}
+		return i, err
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if strict && f != math.Trunc(f) && !math.IsInf(f, 0) {
+			return 0, fmt.Errorf("%w: %g", ErrFractional, f)
+		}
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %g does not fit in int64", ErrOutOfRange, f)
+		}
+		return int64(f), nil
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(v).Int(), nil
+	case uint, uint8, uint16, uint32, uint64:
+		u := reflect.ValueOf(v).Uint()
+		if u > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d does not fit in int64", ErrOutOfRange, u)
+		}
+		return int64(u), nil
+	}
+	return 0, ErrTypeMismatch
+}
+
+// coerceUint64 is Uint64 with range and sign checking; fractions
+// are truncated unless `strict` is set
+func coerceUint64(v interface{}, strict bool) (uint64, error) {
+	switch v.(type) {
+	case json.Number:
+		s := v.(json.Number).String()
+		if f, err := strconv.ParseFloat(s, 64); err == nil && f < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrNegative, s)
+		}
+		digits, err := integerDigits(s, !strict)
+		if err != nil {
+			return 0, err
+		}
+		u, err := strconv.ParseUint(strings.TrimPrefix(digits, "-"), 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("%w: %s does not fit in uint64", ErrOutOfRange, v)
+		}
+		return u, err
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if f < 0 {
+			return 0, fmt.Errorf("%w: %g", ErrNegative, f)
+		}
+		if strict && f != math.Trunc(f) && !math.IsInf(f, 0) {
+			return 0, fmt.Errorf("%w: %g", ErrFractional, f)
+		}
+		if math.IsNaN(f) || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%w: %g does not fit in uint64", ErrOutOfRange, f)
+		}
+		return uint64(f), nil
+	case int, int8, int16, int32, int64:
+		i := reflect.ValueOf(v).Int()
+		if i < 0 {
+			return 0, fmt.Errorf("%w: %d", ErrNegative, i)
+		}
+		return uint64(i), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(v).Uint(), nil
+	}
+	return 0, ErrTypeMismatch
+}
+
+// integerDigits rewrites a number in JSON syntax, such as "1.5e3", as the
+// plain decimal integer it denotes; if it is not one, the fraction is dropped
+// when `truncate` is set and ErrFractional returned otherwise
+func integerDigits(s string, truncate bool) (string, error) {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	mantissa, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa = s[:i]
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			if strings.Trim(mantissa, "0.") == "" {
+				return "0", nil
+			}
+			if strings.HasPrefix(s[i+1:], "-") {
+				if truncate {
+					return "0", nil
+				}
+				return "", fmt.Errorf("%w: %s%s", ErrFractional, sign, s)
+			}
+			return "", fmt.Errorf("%w: %s%s", ErrOutOfRange, sign, s)
+		}
+		exp = e
+	}
+	intPart, frac := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, frac = mantissa[:i], mantissa[i+1:]
+	}
+
+	all := intPart + frac
+	digits := strings.TrimLeft(all, "0")
+	if digits == "" {
+		return "0", nil
+	}
+	// position of the decimal point within `digits`
+	point := len(intPart) + exp - (len(all) - len(digits))
+	if point < len(digits) {
+		rest := digits
+		if point > 0 {
+			rest = digits[point:]
+		}
+		if !truncate && strings.Trim(rest, "0") != "" {
+			return "", fmt.Errorf("%w: %s%s", ErrFractional, sign, s)
+		}
+	}
+	if point > 20 {
+		return "", fmt.Errorf("%w: %s%s", ErrOutOfRange, sign, s)
+	}
+	if point <= 0 {
+		return "0", nil
+	}
+	if point < len(digits) {
+		return sign + digits[:point], nil
+	}
+	return sign + digits + strings.Repeat("0", point-len(digits)), nil
 }
@@ -3,6 +3,7 @@ package simplejson
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
@@ -125,3 +126,164 @@ func TestSimplejsonGo11(t *testing.T) {
 		t.Errorf("got %#v", n)
 	}
 }
+
+func TestStrictNumbers(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"int": 10,
+		"exp": 1.5e3,
+		"zero_frac": 2.000,
+		"frac": 3.9,
+		"tiny": 1e-5,
+		"neg": -1,
+		"neg_frac": -0.5,
+		"big": 1e30,
+		"uint64": 18446744073709551615
+	}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	js.Set("go_float", 3.9)
+	js.Set("go_neg", int8(-1))
+	js.Set("go_uint", uint64(18446744073709551615))
+
+	ints := []struct {
+		key      string
+		expected int64
+		err      error
+	}{
+		{"int", 10, nil},
+		{"exp", 1500, nil},
+		{"zero_frac", 2, nil},
+		{"frac", 0, ErrFractional},
+		{"tiny", 0, ErrFractional},
+		{"neg", -1, nil},
+		{"big", 0, ErrOutOfRange},
+		{"uint64", 0, ErrOutOfRange},
+		{"go_float", 0, ErrFractional},
+		{"go_uint", 0, ErrOutOfRange},
+	}
+	for _, tc := range ints {
+		i, err := js.Get(tc.key).Int64Strict()
+		if !errors.Is(err, tc.err) || i != tc.expected {
+			t.Errorf("%s: got %#v %v", tc.key, i, err)
+		}
+	}
+
+	uints := []struct {
+		key      string
+		expected uint64
+		err      error
+	}{
+		{"int", 10, nil},
+		{"exp", 1500, nil},
+		{"frac", 0, ErrFractional},
+		{"neg", 0, ErrNegative},
+		{"neg_frac", 0, ErrNegative},
+		{"go_neg", 0, ErrNegative},
+		{"big", 0, ErrOutOfRange},
+		{"uint64", 18446744073709551615, nil},
+	}
+	for _, tc := range uints {
+		u, err := js.Get(tc.key).Uint64Strict()
+		if !errors.Is(err, tc.err) || u != tc.expected {
+			t.Errorf("%s: got %#v %v", tc.key, u, err)
+		}
+	}
+
+	if i, err := js.Get("exp").IntStrict(); err != nil || i != 1500 {
+		t.Errorf("got %#v %v", i, err)
+	}
+
+	// the lenient accessors are unchanged
+	if i, _ := js.Get("go_float").Int(); i != 3 {
+		t.Errorf("got %#v", i)
+	}
+	if u, _ := js.Get("go_neg").Uint64(); u != 18446744073709551615 {
+		t.Errorf("got %#v", u)
+	}
+}
+
+func TestLooseCoercion(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"int_str": "42",
+		"neg_str": "-7",
+		"float_str": "3.5",
+		"exp_str": "1e3",
+		"padded": " 42",
+		"plus": "+1",
+		"hex": "0x10",
+		"nan": "NaN",
+		"true_str": "true",
+		"false_str": "false",
+		"True_str": "True",
+		"int": 10,
+		"float": 5.150,
+		"bool": true,
+		"null": null
+	}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	if i, err := js.Get("int_str").IntLoose(); err != nil || i != 42 {
+		t.Errorf("got %#v %v", i, err)
+	}
+	if i, err := js.Get("neg_str").Int64Loose(); err != nil || i != -7 {
+		t.Errorf("got %#v %v", i, err)
+	}
+	if u, err := js.Get("int_str").Uint64Loose(); err != nil || u != 42 {
+		t.Errorf("got %#v %v", u, err)
+	}
+	if f, err := js.Get("float_str").Float64Loose(); err != nil || f != 3.5 {
+		t.Errorf("got %#v %v", f, err)
+	}
+	if f, err := js.Get("exp_str").Float64Loose(); err != nil || f != 1000 {
+		t.Errorf("got %#v %v", f, err)
+	}
+	if i, err := js.Get("int").IntLoose(); err != nil || i != 10 {
+		t.Errorf("got %#v %v", i, err)
+	}
+	for _, key := range []string{"padded", "plus", "hex", "nan", "true_str", "null"} {
+		if _, err := js.Get(key).Float64Loose(); err == nil {
+			t.Errorf("%s: expected error", key)
+		}
+	}
+	// "3.5" parses as a number, but Int64 does not truncate json.Number
+	if _, err := js.Get("float_str").Int64Loose(); err == nil {
+		t.Errorf("expected error")
+	}
+
+	if b, err := js.Get("true_str").BoolLoose(); err != nil || !b {
+		t.Errorf("got %#v %v", b, err)
+	}
+	if b, err := js.Get("false_str").BoolLoose(); err != nil || b {
+		t.Errorf("got %#v %v", b, err)
+	}
+	if b, err := js.Get("bool").BoolLoose(); err != nil || !b {
+		t.Errorf("got %#v %v", b, err)
+	}
+	for _, key := range []string{"True_str", "int_str", "int"} {
+		if _, err := js.Get(key).BoolLoose(); err == nil {
+			t.Errorf("%s: expected error", key)
+		}
+	}
+
+	js.Set("go_int", 7)
+	js.Set("go_float", 2.5)
+	strs := map[string]string{
+		"int_str":  "42",
+		"int":      "10",
+		"float":    "5.150",
+		"bool":     "true",
+		"go_int":   "7",
+		"go_float": "2.5",
+	}
+	for key, expected := range strs {
+		if s, err := js.Get(key).StringLoose(); err != nil || s != expected {
+			t.Errorf("%s: got %#v %v", key, s, err)
+		}
+	}
+	if _, err := js.Get("null").StringLoose(); err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -0,0 +1,368 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// NewPreservingJson returns a pointer to a new `Json` object after unmarshaling
+// `body` bytes like NewOrderedJson, but also retaining the source text
+//
+// Encode and EncodePretty on the document write that text back with only the
+// values that have changed since it was parsed re-encoded, keeping the author's
+// indentation, spacing, key order, number formatting and string escapes
+// everywhere else:
+//
+//	js, err := simplejson.NewPreservingJson(manifest)
+//	js.Set("version", "1.4.0")
+//	out, err := js.Encode()
+//
+// new members and elements copy the layout of the last existing one of their
+// object or array, and new objects and arrays are indented to match the
+// document. Values are matched to the source by key and index, so removing an
+// array element re-encodes those after it that have shifted position. Encoding
+// a value obtained from the document with Get and similar methods writes it as
+// NewOrderedJson would.
+func NewPreservingJson(body []byte) (*Json, error) {
+	p := &sourceParser{
+		body:  body,
+		dec:   json.NewDecoder(bytes.NewReader(body)),
+		order: newKeyOrder(),
+	}
+	p.dec.UseNumber()
+	data, root, err := p.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if i := p.space(p.pos); i < len(body) {
+		return nil, fmt.Errorf("invalid character %q after top-level value", body[i])
+	}
+	s := &sourceText{
+		root:   root,
+		lead:   body[:root.start],
+		trail:  body[p.pos:],
+		indent: p.indent,
+	}
+	return &Json{data: data, order: p.order, source: s}, nil
+}
+
+// sourceText is the source of a document created by NewPreservingJson
+type sourceText struct {
+	root        *sourceValue
+	lead, trail []byte // the text around the root value
+	indent      string // one level of indentation, or "" if there is none
+}
+
+// sourceValue is the source of one value
+type sourceValue struct {
+	start   int
+	raw     []byte
+	kind    Kind
+	value   interface{}              // the decoded value of a scalar
+	members map[string]*sourceMember // the members of an object
+	elems   []*sourceMember          // the elements of an array
+	first   *sourceMember            // the first member of an object
+	last    *sourceMember            // the last member or element
+	closing []byte                   // the text before the closing bracket
+}
+
+// sourceMember is the source of an object member or array element,
+// the latter having no key and mid
+type sourceMember struct {
+	lead  []byte // the text after the opening bracket or preceding comma
+	key   []byte
+	mid   []byte // the text between the key and value, including the colon
+	value *sourceValue
+	trail []byte // the text before the following comma
+}
+
+type sourceParser struct {
+	body   []byte
+	dec    *json.Decoder
+	pos    int // the end of the last token read from dec
+	order  *keyOrder
+	indent string
+}
+
+// space returns the offset of the first non-whitespace byte at or after `i`
+func (p *sourceParser) space(i int) int {
+	for i < len(p.body) {
+		switch p.body[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func (p *sourceParser) token() (json.Token, error) {
+	tok, err := p.dec.Token()
+	p.pos = int(p.dec.InputOffset())
+	return tok, err
+}
+
+// next returns the offset of the next token, skipping
+// over the comma or colon that may precede it
+func (p *sourceParser) next() int {
+	i := p.space(p.pos)
+	if i < len(p.body) && (p.body[i] == ',' || p.body[i] == ':') {
+		i = p.space(i + 1)
+	}
+	return i
+}
+
+// member records the layout of an object member or array element and
+// returns the offset its lead starts at, after the preceding comma
+func (p *sourceParser) member(prev *sourceMember) int {
+	if prev == nil {
+		return p.pos
+	}
+	i := p.space(p.pos)
+	prev.trail = p.body[p.pos:i]
+	return i + 1
+}
+
+// value reads the next value, `depth` levels below the root
+func (p *sourceParser) value(depth int) (interface{}, *sourceValue, error) {
+	start := p.next()
+	tok, err := p.token()
+	if err != nil {
+		return nil, nil, err
+	}
+	sv := &sourceValue{start: start}
+
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]interface{})
+		e := p.order.entry(m, true)
+		sv.kind = Object
+		sv.members = make(map[string]*sourceMember)
+		for p.dec.More() {
+			from := p.member(sv.last)
+			tok, err := p.token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key := tok.(string)
+			keyStart := p.space(from)
+			mem := &sourceMember{
+				lead: p.body[from:keyStart],
+				key:  p.body[keyStart:p.pos],
+			}
+			keyEnd := p.pos
+			val, mv, err := p.value(depth + 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			mem.mid = p.body[keyEnd:mv.start]
+			mem.value = mv
+			p.detectIndent(depth, mem.lead)
+			e.add(key)
+			m[key] = val
+			sv.members[key] = mem
+			if sv.first == nil {
+				sv.first = mem
+			}
+			sv.last = mem
+		}
+		if err := p.close(sv); err != nil {
+			return nil, nil, err
+		}
+		return m, sv, nil
+	case json.Delim('['):
+		a := []interface{}{}
+		sv.kind = Array
+		for p.dec.More() {
+			from := p.member(sv.last)
+			val, ev, err := p.value(depth + 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			mem := &sourceMember{lead: p.body[from:ev.start], value: ev}
+			p.detectIndent(depth, mem.lead)
+			a = append(a, val)
+			sv.elems = append(sv.elems, mem)
+			sv.last = mem
+		}
+		if err := p.close(sv); err != nil {
+			return nil, nil, err
+		}
+		return a, sv, nil
+	}
+
+	sv.kind = kindOf(tok)
+	sv.value = tok
+	sv.raw = p.body[start:p.pos]
+	return tok, sv, nil
+}
+
+// close reads the closing bracket of the object or array `sv`; the
+// text before it is kept as `closing` rather than the trail of the last
+// member or element, so that it stays in place if more are added
+func (p *sourceParser) close(sv *sourceValue) error {
+	sv.closing = p.body[p.pos:p.space(p.pos)]
+	if _, err := p.token(); err != nil {
+		return err
+	}
+	sv.raw = p.body[sv.start:p.pos]
+	return nil
+}
+
+// detectIndent takes the indentation of the document from the first
+// member or element of the root that starts on a new line
+func (p *sourceParser) detectIndent(depth int, lead []byte) {
+	if depth != 0 || p.indent != "" {
+		return
+	}
+	if i := bytes.LastIndexByte(lead, '\n'); i >= 0 {
+		p.indent = string(lead[i+1:])
+	}
+}
+
+// encode writes `v`, the current root value of the document, reusing
+// the source of every value that has not changed
+func (s *sourceText) encode(o *keyOrder, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(s.lead)
+	prefix, _ := linePrefix(s.lead, "")
+	if err := s.encodeValue(&buf, o, s.root, v, prefix, true); err != nil {
+		return nil, err
+	}
+	buf.Write(s.trail)
+	return buf.Bytes(), nil
+}
+
+// linePrefix returns the indentation of a value preceded by `lead`, and
+// whether it starts a new line; otherwise it shares the line of its parent,
+// which is indented by `prefix`
+func linePrefix(lead []byte, prefix string) (string, bool) {
+	if i := bytes.LastIndexByte(lead, '\n'); i >= 0 {
+		return string(lead[i+1:]), true
+	}
+	return prefix, false
+}
+
+// encodeValue writes `v` at a location whose source is `src`, or nil if it
+// is new; values that start a new line are indented by `prefix` and new
+// objects and arrays among them spread over several lines if `multiline`
+func (s *sourceText) encodeValue(buf *bytes.Buffer, o *keyOrder, src *sourceValue, v interface{}, prefix string, multiline bool) error {
+	if src == nil {
+		return s.encodeNew(buf, o, v, prefix, multiline)
+	}
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if src.kind != Object {
+			break
+		}
+		if len(x) == 0 {
+			s.encodeEmpty(buf, src, "{}")
+			return nil
+		}
+		if src.last == nil {
+			break
+		}
+		buf.WriteByte('{')
+		for i, k := range o.keys(x) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			mem, ok := src.members[k]
+			if !ok {
+				mem = &sourceMember{lead: src.last.lead, mid: src.last.mid}
+				mem.key, _ = json.Marshal(k)
+			}
+			// the text after the opening brace stays first, and that
+			// after a comma is taken from another member
+			switch {
+			case i == 0 && mem != src.first:
+				m := *mem
+				m.lead = src.first.lead
+				mem = &m
+			case i > 0 && mem == src.first:
+				m := *mem
+				m.lead = src.last.lead
+				mem = &m
+			}
+			if err := s.encodeMember(buf, o, mem, x[k], prefix); err != nil {
+				return err
+			}
+		}
+		buf.Write(src.closing)
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		if src.kind != Array {
+			break
+		}
+		if len(x) == 0 {
+			s.encodeEmpty(buf, src, "[]")
+			return nil
+		}
+		if src.last == nil {
+			break
+		}
+		buf.WriteByte('[')
+		for i, av := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			mem := &sourceMember{lead: src.last.lead}
+			if i < len(src.elems) {
+				mem = src.elems[i]
+			}
+			if err := s.encodeMember(buf, o, mem, av, prefix); err != nil {
+				return err
+			}
+		}
+		buf.Write(src.closing)
+		buf.WriteByte(']')
+		return nil
+	case nil, bool, string, json.Number:
+		if src.value == v && src.kind != Object && src.kind != Array {
+			buf.Write(src.raw)
+			return nil
+		}
+	}
+	return s.encodeNew(buf, o, v, prefix, multiline)
+}
+
+// encodeEmpty writes an empty object or array, keeping its
+// source unless it had members or elements that were removed
+func (s *sourceText) encodeEmpty(buf *bytes.Buffer, src *sourceValue, empty string) {
+	if src.last == nil {
+		buf.Write(src.raw)
+		return
+	}
+	buf.WriteString(empty)
+}
+
+// encodeMember writes an object member or array element laid out like `mem`
+func (s *sourceText) encodeMember(buf *bytes.Buffer, o *keyOrder, mem *sourceMember, v interface{}, prefix string) error {
+	buf.Write(mem.lead)
+	buf.Write(mem.key)
+	buf.Write(mem.mid)
+	prefix, multiline := linePrefix(mem.lead, prefix)
+	if err := s.encodeValue(buf, o, mem.value, v, prefix, multiline); err != nil {
+		return err
+	}
+	buf.Write(mem.trail)
+	return nil
+}
+
+// encodeNew writes a value that has no source, indented to
+// match the document if it is to span several lines
+func (s *sourceText) encodeNew(buf *bytes.Buffer, o *keyOrder, v interface{}, prefix string, multiline bool) error {
+	b, err := o.marshal(v)
+	if err != nil {
+		return err
+	}
+	if multiline && s.indent != "" {
+		return json.Indent(buf, b, prefix, s.indent)
+	}
+	buf.Write(b)
+	return nil
+}
@@ -0,0 +1,154 @@
+package simplejson
+
+import (
+	"testing"
+)
+
+func mustPreserving(t *testing.T, s string) *Json {
+	t.Helper()
+	js, err := NewPreservingJson([]byte(s))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	return js
+}
+
+func TestPreservingRoundTrip(t *testing.T) {
+	in := "\n{ \"b\" :1.50e0,\n\t\"a\": [ 1 ,\"\\u00e9\" , {}, [ ] ],\"c\" : { \"x\" : null }\n}\n\n"
+	js := mustPreserving(t, in)
+	if b, _ := js.Encode(); string(b) != in {
+		t.Errorf("got %q", b)
+	}
+	if b, _ := js.EncodePretty(); string(b) != in {
+		t.Errorf("got %q", b)
+	}
+	if v := js.Get("a").GetIndex(1).MustString(); v != "é" {
+		t.Errorf("got %#v", v)
+	}
+	if b, _ := js.Get("c").Encode(); string(b) != `{"x":null}` {
+		t.Errorf("got %s", b)
+	}
+
+	for _, s := range []string{`{"a":`, `{"a" 1}`, `[1,]`, ``, `{"a":1} {"b":2}`, `1 x`} {
+		if _, err := NewPreservingJson([]byte(s)); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestPreservingEdits(t *testing.T) {
+	js := mustPreserving(t, `{
+    "name": "widget",
+    "version": "1.2.3",
+    "tags": ["a", "b"],
+    "deps": {
+        "left-pad": "^1.0.0",
+        "chalk": "2.x"
+    },
+    "scripts": {}
+}
+`)
+	js.Set("version", "1.3.0")
+	js.Set("name", "widget")
+	js.Get("deps").Del("left-pad")
+	js.Get("deps").Set("lodash", "4.17.21")
+	js.Get("tags").Append("c")
+	js.Set("files", []interface{}{"dist", map[string]interface{}{"src": true}})
+	js.Get("scripts").Set("test", "go test")
+	want := `{
+    "name": "widget",
+    "version": "1.3.0",
+    "tags": ["a", "b", "c"],
+    "deps": {
+        "chalk": "2.x",
+        "lodash": "4.17.21"
+    },
+    "scripts": {
+        "test": "go test"
+    },
+    "files": [
+        "dist",
+        {
+            "src": true
+        }
+    ]
+}
+`
+	if b, _ := js.Encode(); string(b) != want {
+		t.Errorf("got %s", b)
+	}
+
+	js.Del("deps")
+	js.Del("files")
+	js.Del("scripts")
+	js.Get("tags").SetIndex(0, "z")
+	want = `{
+    "name": "widget",
+    "version": "1.3.0",
+    "tags": ["z", "b", "c"]
+}
+`
+	if b, _ := js.Encode(); string(b) != want {
+		t.Errorf("got %s", b)
+	}
+
+	// a value whose type changes is encoded anew
+	js.Set("tags", 7)
+	js.Get("name").SetPath([]string{"first"}, "w")
+	want = `{
+    "name": {
+        "first": "w"
+    },
+    "version": "1.3.0",
+    "tags": 7
+}
+`
+	if b, _ := js.Encode(); string(b) != want {
+		t.Errorf("got %s", b)
+	}
+
+	c := js.Clone()
+	c.Set("version", "2.0.0")
+	if b, _ := c.Encode(); string(b) != `{
+    "name": {
+        "first": "w"
+    },
+    "version": "2.0.0",
+    "tags": 7
+}
+` {
+		t.Errorf("got %s", b)
+	}
+	if v := js.Get("version").MustString(); v != "1.3.0" {
+		t.Errorf("got %#v", v)
+	}
+}
+
+func TestPreservingCompact(t *testing.T) {
+	js := mustPreserving(t, `{"a":{"b":1}, "c":[2]}`)
+	js.Get("a").Set("d", map[string]interface{}{"e": 3})
+	js.Get("c").Append(4)
+	if b, _ := js.Encode(); string(b) != `{"a":{"b":1,"d":{"e":3}}, "c":[2,4]}` {
+		t.Errorf("got %s", b)
+	}
+
+	// removing the first member keeps the layout of the rest
+	js = mustPreserving(t, `{"a": "^1", "b": "^2", "c": "^3"}`)
+	js.Del("a")
+	if b, _ := js.Encode(); string(b) != `{"b": "^2", "c": "^3"}` {
+		t.Errorf("got %s", b)
+	}
+	js.Set("a", "^4")
+	if b, _ := js.Encode(); string(b) != `{"b": "^2", "c": "^3", "a": "^4"}` {
+		t.Errorf("got %s", b)
+	}
+
+	js = mustPreserving(t, ` 42 `)
+	if b, _ := js.Encode(); string(b) != ` 42 ` {
+		t.Errorf("got %q", b)
+	}
+	js.SetPath(nil, "x")
+	if b, _ := js.Encode(); string(b) != ` "x" ` {
+		t.Errorf("got %q", b)
+	}
+}
@@ -2,6 +2,7 @@ package simplejson
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -341,3 +342,292 @@ func TestPathWillOverwriteExisting(t *testing.T) {
 		t.Errorf("got %#v", s)
 	}
 }
+
+func TestSetBranch(t *testing.T) {
+	js, err := NewJson([]byte(`{"items": [{"name": "a"}], "scalar": 1, "nothing": null}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	if err := js.SetBranch([]interface{}{"items", 2, "name"}, "c"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := js.SetBranch([]interface{}{"items", 0, "tags", 1}, "x"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := js.SetBranch([]interface{}{"nothing", "a"}, true); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := js.SetBranch([]interface{}{"grid", 1, 0}, 5); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	expected := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "tags": []interface{}{nil, "x"}},
+			nil,
+			map[string]interface{}{"name": "c"},
+		},
+		"scalar":  json.Number("1"),
+		"nothing": map[string]interface{}{"a": true},
+		"grid":    []interface{}{nil, []interface{}{5}},
+	}
+	if !reflect.DeepEqual(js.Interface(), expected) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	cases := []struct {
+		branch  []interface{}
+		pointer string
+		index   int
+	}{
+		{[]interface{}{"scalar", "a"}, "/scalar/a", 1},
+		{[]interface{}{"items", "a"}, "/items/a", 1},
+		{[]interface{}{"items", 0, 1}, "/items/0/1", 2},
+		{[]interface{}{"items", -1}, "/items/-1", 1},
+		{[]interface{}{"items", 1.5}, "/items/1.5", 1},
+		{[]interface{}{"items", 1 << 30}, "/items/1073741824", 1},
+		{[]interface{}{"grid", 0, maxBranchPadding + 1}, "/grid/0/65537", 2},
+	}
+	for _, tc := range cases {
+		err := js.SetBranch(tc.branch, "x")
+		pe, ok := err.(*PointerError)
+		if !ok || pe.Pointer != tc.pointer || pe.Index != tc.index {
+			t.Errorf("%v: got %#v", tc.branch, err)
+		}
+	}
+	if !reflect.DeepEqual(js.Interface(), expected) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	js = New()
+	if err := js.SetBranch([]interface{}{}, []interface{}{}); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := js.SetBranch([]interface{}{3}, "d"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if a := js.MustArray(); len(a) != 4 || a[3] != "d" {
+		t.Errorf("got %#v", a)
+	}
+}
+
+func TestArrayMutation(t *testing.T) {
+	js, err := NewJson([]byte(`{"test": {"array": [1, 2, 3], "nested": [[1]], "string": "x"}}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	arr := js.Get("test").Get("array")
+	if err := arr.Append(4, 5); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := arr.Insert(0, 0); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := arr.RemoveIndex(2); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if err := arr.SetIndex(1, "one"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	expected := []interface{}{0, "one", json.Number("3"), 4, 5}
+	if a := js.GetPath("test", "array").MustArray(); !reflect.DeepEqual(a, expected) {
+		t.Errorf("got %#v", a)
+	}
+
+	if err := js.Get("test").Get("nested").GetIndex(0).Append(2); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if a := js.GetPath("test", "nested").GetIndex(0).MustArray(); len(a) != 2 {
+		t.Errorf("got %#v", a)
+	}
+
+	if err := js.Get("test").Get("missing").Append("a"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if a := js.GetPath("test", "missing").MustStringArray(); !reflect.DeepEqual(a, []string{"a"}) {
+		t.Errorf("got %#v", a)
+	}
+
+	if err := js.AppendPath([]interface{}{"test", "nested", 1}, "x", "y"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if a := js.GetPath("test", "nested").GetIndex(1).MustStringArray(); !reflect.DeepEqual(a, []string{"x", "y"}) {
+		t.Errorf("got %#v", a)
+	}
+
+	for _, err := range []error{
+		arr.SetIndex(5, 0),
+		arr.Insert(6, 0),
+		arr.RemoveIndex(-1),
+		js.Get("test").Get("string").Append(1),
+		js.AppendPath([]interface{}{"test", "string"}, 1),
+	} {
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	}
+
+	err = arr.SetIndex(5, 0)
+	var perr *PathError
+	if !errors.As(err, &perr) || perr.Path != "/test/array/5" || !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %#v", err)
+	}
+
+	// missing objects above a new array are created
+	js = New()
+	if err := js.Get("a").Get("b").Append(1); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if b, _ := js.Encode(); string(b) != `{"a":{"b":[1]}}` {
+		t.Errorf("got %s", b)
+	}
+
+	// but a write that cannot be stored is reported
+	js = mustJson(t, `{"s": "x", "l": [], "n": null}`)
+	for _, c := range []*Json{
+		js.Get("s").Get("b"),
+		js.Get("l").GetIndex(0),
+		js.Get("l").GetIndex(0).Get("b"),
+		js.Get("n").Get("b"),
+	} {
+		if err := c.Append(1); !errors.Is(err, ErrNotFound) {
+			t.Errorf("got %#v", err)
+		}
+	}
+	if b, _ := js.Encode(); string(b) != `{"l":[],"n":null,"s":"x"}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestDelPath(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"user": {"credentials": {"token": "secret", "id": 1}},
+		"items": [{"a": 1, "b": 2}, 2, [3, 4]]
+	}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	cases := []struct {
+		branch  []interface{}
+		removed bool
+	}{
+		{[]interface{}{"user", "credentials", "token"}, true},
+		{[]interface{}{"user", "credentials", "token"}, false},
+		{[]interface{}{"items", 0, "b"}, true},
+		{[]interface{}{"items", 2, 0}, true},
+		{[]interface{}{"items", 1}, true},
+		{[]interface{}{"items", 5}, false},
+		{[]interface{}{"items", "0"}, false},
+		{[]interface{}{"user", 0}, false},
+		{[]interface{}{"user", 1.5}, false},
+		{[]interface{}{}, false},
+	}
+	for _, tc := range cases {
+		if removed := js.DelPath(tc.branch...); removed != tc.removed {
+			t.Errorf("%v: got %#v expected %#v", tc.branch, removed, tc.removed)
+		}
+	}
+
+	expected := map[string]interface{}{
+		"user":  map[string]interface{}{"credentials": map[string]interface{}{"id": json.Number("1")}},
+		"items": []interface{}{map[string]interface{}{"a": json.Number("1")}, []interface{}{json.Number("4")}},
+	}
+	if !reflect.DeepEqual(js.Interface(), expected) {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	items := js.Get("items")
+	if !items.DelPath(0) {
+		t.Errorf("expected removal")
+	}
+	if a := js.Get("items").MustArray(); len(a) != 1 {
+		t.Errorf("got %#v", a)
+	}
+}
+
+func TestTypedArrays(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"ints": [1, 2, null, 3],
+		"floats": [1.5, 2, -3e2],
+		"bools": [true, false, null],
+		"maps": [{"a": 1}, null, {"b": 2}],
+		"bad_ints": [1, "two", 3],
+		"bad_bools": [true, 1],
+		"bad_maps": [{}, []],
+		"big": [1, 1e30]
+	}`))
+	if err != nil {
+		t.Fatalf("err %#v", err)
+	}
+
+	if a, err := js.Get("ints").IntArray(); err != nil || !reflect.DeepEqual(a, []int{1, 2, 0, 3}) {
+		t.Errorf("got %#v %v", a, err)
+	}
+	if a, err := js.Get("ints").Int64Array(); err != nil || !reflect.DeepEqual(a, []int64{1, 2, 0, 3}) {
+		t.Errorf("got %#v %v", a, err)
+	}
+	if a, err := js.Get("floats").Float64Array(); err != nil || !reflect.DeepEqual(a, []float64{1.5, 2, -300}) {
+		t.Errorf("got %#v %v", a, err)
+	}
+	if a, err := js.Get("bools").BoolArray(); err != nil || !reflect.DeepEqual(a, []bool{true, false, false}) {
+		t.Errorf("got %#v %v", a, err)
+	}
+
+	maps, err := js.Get("maps").MapArray()
+	if err != nil || len(maps) != 3 || maps[1] != nil {
+		t.Fatalf("got %#v %v", maps, err)
+	}
+	maps[0]["c"] = 3
+	if i := js.Get("maps").GetIndex(0).Get("c").MustInt(); i != 3 {
+		t.Errorf("got %#v", i)
+	}
+
+	items, err := js.Get("maps").JsonArray()
+	if err != nil || len(items) != 3 {
+		t.Fatalf("got %#v %v", items, err)
+	}
+	items[1].SetPath(nil, "x")
+	items[2].Set("d", 4)
+	if s := js.Get("maps").GetIndex(1).MustString(); s != "x" {
+		t.Errorf("got %#v", s)
+	}
+	if i := js.Get("maps").GetIndex(2).Get("d").MustInt(); i != 4 {
+		t.Errorf("got %#v", i)
+	}
+
+	errs := map[string]error{
+		"/bad_ints/1":  func() error { _, err := js.Get("bad_ints").IntArray(); return err }(),
+		"/bad_bools/1": func() error { _, err := js.Get("bad_bools").BoolArray(); return err }(),
+		"/bad_maps/1":  func() error { _, err := js.Get("bad_maps").MapArray(); return err }(),
+		"/big/1":       func() error { _, err := js.Get("big").Int64Array(); return err }(),
+	}
+	for path, err := range errs {
+		var perr *PathError
+		if !errors.As(err, &perr) || perr.Path != path {
+			t.Errorf("%s: got %v", path, err)
+		}
+	}
+
+	if a := js.Get("bad_ints").MustIntArray([]int{9}); !reflect.DeepEqual(a, []int{9}) {
+		t.Errorf("got %#v", a)
+	}
+	if a := js.Get("missing").MustFloat64Array(); a != nil {
+		t.Errorf("got %#v", a)
+	}
+	if a := js.Get("ints").MustInt64Array(); len(a) != 4 {
+		t.Errorf("got %#v", a)
+	}
+	if a := js.Get("bools").MustBoolArray(); len(a) != 3 {
+		t.Errorf("got %#v", a)
+	}
+	// element 1 of maps was replaced by a string above
+	if a := js.Get("maps").MustMapArray(); a != nil {
+		t.Errorf("got %#v", a)
+	}
+	if a := js.Get("ints").MustJsonArray(); len(a) != 4 {
+		t.Errorf("got %#v", a)
+	}
+}
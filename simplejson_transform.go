@@ -0,0 +1,121 @@
+package simplejson
+
+// Transform rewrites every value in `Json` by calling `fn` with its path and
+// value and storing what it returns, or removing the value if it returns false
+//
+// children are transformed before their parent, so `fn` sees objects and arrays
+// holding already transformed values, and `Json` itself is visited last at the
+// empty path (removing it leaves null). Object members are visited in document
+// order if `Json` was created by NewOrderedJson and in ascending key order
+// otherwise, and array elements by index; paths refer to the original document,
+// so they are unaffected by the removal of earlier elements:
+//
+//	// strip empty strings, then any objects left empty
+//	js.Transform(func(path simplejson.Path, v interface{}) (interface{}, bool) {
+//		switch x := v.(type) {
+//		case string:
+//			return v, x != ""
+//		case map[string]interface{}:
+//			return v, len(x) > 0
+//		}
+//		return v, true
+//	})
+func (j *Json) Transform(fn func(path Path, v interface{}) (interface{}, bool)) {
+	j.unshare()
+	order := j.keyOrder()
+	v, keep := transform(order, Path{}, j.data, fn)
+	if !keep {
+		v = nil
+	}
+	order.release(j.data, v)
+	j.update(v)
+}
+
+func transform(order *keyOrder, path Path, v interface{}, fn func(Path, interface{}) (interface{}, bool)) (interface{}, bool) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for _, k := range order.keys(x) {
+			nv, keep := transform(order, append(path[:len(path):len(path)], k), x[k], fn)
+			if keep {
+				order.set(x, k, nv)
+			} else {
+				order.del(x, k)
+			}
+		}
+	case []interface{}:
+		// a new array, as removing elements from `x` in place would
+		// shift them under anyone else holding it
+		out := make([]interface{}, 0, len(x))
+		for i, e := range x {
+			nv, keep := transform(order, append(path[:len(path):len(path)], i), e, fn)
+			if keep {
+				out = append(out, nv)
+			}
+		}
+		v = out
+	}
+	return fn(path, v)
+}
+
+// MapValues replaces every value in `Json` that is not an object or
+// array with the result of `fn`
+//
+//	// round every float to two decimal places
+//	js.MapValues(func(v interface{}) interface{} {
+//		if f, err := v.(json.Number).Float64(); err == nil {
+//			return math.Round(f*100) / 100
+//		}
+//		return v
+//	})
+func (j *Json) MapValues(fn func(v interface{}) interface{}) {
+	j.Transform(func(_ Path, v interface{}) (interface{}, bool) {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return v, true
+		}
+		return fn(v), true
+	})
+}
+
+// RenameKeys replaces the key of every object member in `Json`, at any
+// depth, with the result of `fn`
+//
+// renamed members keep their position in key order, and when several keys
+// of one object are renamed to the same key, the member whose original key
+// comes last in that order wins:
+//
+//	js.RenameKeys(strings.ToLower)
+func (j *Json) RenameKeys(fn func(key string) string) {
+	order := j.keyOrder()
+	j.Transform(func(_ Path, v interface{}) (interface{}, bool) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v, true
+		}
+		renamed := make(map[string]interface{}, len(m))
+		for _, k := range order.keys(m) {
+			order.set(renamed, fn(k), m[k])
+		}
+		return renamed, true
+	})
+}
+
+// FilterKeys removes every object member in `Json`, at any depth,
+// whose key `fn` returns false for
+//
+//	js.FilterKeys(func(key string) bool {
+//		return !strings.HasPrefix(key, "_")
+//	})
+func (j *Json) FilterKeys(fn func(key string) bool) {
+	order := j.keyOrder()
+	j.Transform(func(_ Path, v interface{}) (interface{}, bool) {
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				if !fn(k) {
+					order.del(m, k)
+				}
+			}
+		}
+		return v, true
+	})
+}
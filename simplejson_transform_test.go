@@ -0,0 +1,124 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	js := mustJson(t, `{
+		"name": "",
+		"tags": ["", "a", "", "b"],
+		"nested": {"empty": "", "inner": {"x": ""}},
+		"n": 1
+	}`)
+
+	var paths []string
+	js.Transform(func(path Path, v interface{}) (interface{}, bool) {
+		paths = append(paths, path.Pointer())
+		switch x := v.(type) {
+		case string:
+			return v, x != ""
+		case map[string]interface{}:
+			return v, len(x) > 0
+		}
+		return v, true
+	})
+
+	if !js.Equal(mustJson(t, `{"tags": ["a", "b"], "n": 1}`)) {
+		t.Errorf("got %#v", js.Interface())
+	}
+	expected := []string{"/n", "/name", "/nested/empty", "/nested/inner/x", "/nested/inner", "/nested",
+		"/tags/0", "/tags/1", "/tags/2", "/tags/3", "/tags", ""}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("got %#v", paths)
+	}
+
+	sub := js.Get("tags")
+	sub.Transform(func(path Path, v interface{}) (interface{}, bool) {
+		if s, ok := v.(string); ok {
+			return strings.ToUpper(s), true
+		}
+		return v, true
+	})
+	if a := js.Get("tags").MustStringArray(); !reflect.DeepEqual(a, []string{"A", "B"}) {
+		t.Errorf("got %#v", a)
+	}
+
+	js.Transform(func(path Path, v interface{}) (interface{}, bool) {
+		return v, len(path) > 0
+	})
+	if js.Interface() != nil {
+		t.Errorf("got %#v", js.Interface())
+	}
+
+	// arrays already held elsewhere are not compacted under their holders
+	js = mustJson(t, `{"a": [1, 2, 3]}`)
+	held := js.Get("a").MustArray()
+	js.Transform(func(path Path, v interface{}) (interface{}, bool) {
+		return v, path.Pointer() != "/a/0"
+	})
+	if !reflect.DeepEqual(held, []interface{}{json.Number("1"), json.Number("2"), json.Number("3")}) {
+		t.Errorf("got %#v", held)
+	}
+	if b, _ := js.Encode(); string(b) != `{"a":[2,3]}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	js := mustJson(t, `{"a": 1.234, "b": [2.345, "x"], "c": {"d": 3}}`)
+	js.MapValues(func(v interface{}) interface{} {
+		if n, ok := v.(json.Number); ok {
+			f, _ := n.Float64()
+			return math.Round(f*10) / 10
+		}
+		return v
+	})
+	if !js.Equal(mustJson(t, `{"a": 1.2, "b": [2.3, "x"], "c": {"d": 3}}`)) {
+		t.Errorf("got %#v", js.Interface())
+	}
+}
+
+func TestRenameKeys(t *testing.T) {
+	js := mustJson(t, `{"userName": "a", "homeAddress": {"zipCode": "1"}, "list": [{"itemId": 1}], "a": 1, "A": 2}`)
+	js.RenameKeys(func(key string) string {
+		var b strings.Builder
+		for _, c := range key {
+			if c >= 'A' && c <= 'Z' {
+				if b.Len() > 0 {
+					b.WriteByte('_')
+				}
+				c += 'a' - 'A'
+			}
+			b.WriteRune(c)
+		}
+		return b.String()
+	})
+	// "A" sorts before "a", so "a" wins the collision
+	if !js.Equal(mustJson(t, `{"user_name": "a", "home_address": {"zip_code": "1"}, "list": [{"item_id": 1}], "a": 1}`)) {
+		t.Errorf("got %#v", js.Interface())
+	}
+}
+
+func TestFilterKeys(t *testing.T) {
+	js := mustJson(t, `{"_id": 1, "name": "a", "nested": {"_rev": 2, "keep": [{"_x": 1, "y": 2}]}}`)
+	js.FilterKeys(func(key string) bool {
+		return !strings.HasPrefix(key, "_")
+	})
+	if !js.Equal(mustJson(t, `{"name": "a", "nested": {"keep": [{"y": 2}]}}`)) {
+		t.Errorf("got %#v", js.Interface())
+	}
+}
+
+func TestTransformLazyClone(t *testing.T) {
+	orig := mustJson(t, `{"a": ["x", ""]}`)
+	clone := orig.LazyClone()
+	clone.FilterKeys(func(string) bool { return false })
+	if !clone.Equal(mustJson(t, `{}`)) || !orig.Equal(mustJson(t, `{"a": ["x", ""]}`)) {
+		t.Errorf("got %#v %#v", clone.Interface(), orig.Interface())
+	}
+}
@@ -0,0 +1,154 @@
+package simplejson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Path locates a value within a document as a sequence of `string` object
+// keys and `int` array indices, the same form SetBranch and DelPath accept
+type Path []interface{}
+
+// String renders the path as an RFC 6901 JSON Pointer
+func (p Path) String() string {
+	return p.Pointer()
+}
+
+// Pointer renders the path as an RFC 6901 JSON Pointer, e.g. `/users/0/name`,
+// where the root is the empty string
+func (p Path) Pointer() string {
+	return branchPointer(p)
+}
+
+// Dotted renders the path in the dotted notation used by JavaScript,
+// e.g. `users[0].name`, quoting keys that are not identifiers as
+// in `labels["app.kubernetes.io/name"]`; the root is the empty string
+func (p Path) Dotted() string {
+	var b strings.Builder
+	for _, seg := range p {
+		switch s := seg.(type) {
+		case string:
+			if !isIdentifier(s) {
+				b.WriteString("[" + strconv.Quote(s) + "]")
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(s)
+		case int:
+			b.WriteString("[" + strconv.Itoa(s) + "]")
+		}
+	}
+	return b.String()
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_' || c == '$':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type walkOp int
+
+const (
+	walkContinue walkOp = iota
+	walkSkip
+	walkStop
+	walkReplace
+)
+
+// WalkAction tells Walk how to proceed after visiting a value
+type WalkAction struct {
+	op    walkOp
+	value interface{}
+}
+
+var (
+	// WalkContinue visits the children of the value, if any, and moves on
+	WalkContinue = WalkAction{op: walkContinue}
+	// WalkSkip moves on without visiting the children of the value
+	WalkSkip = WalkAction{op: walkSkip}
+	// WalkStop ends the walk immediately
+	WalkStop = WalkAction{op: walkStop}
+)
+
+// WalkReplace stores `val` in place of the value and moves on
+// without visiting the children of either
+func WalkReplace(val interface{}) WalkAction {
+	return WalkAction{op: walkReplace, value: val}
+}
+
+// Walk calls `fn` for every value in `Json`, starting with `Json` itself
+// (at the empty path) and visiting each object or array before its
+// children, object members in document order if `Json` was created by
+// NewOrderedJson and in ascending key order otherwise, and array elements
+// by index
+//
+// `node` remembers its location like the result of Get, so `fn` may modify
+// it in place; the returned WalkAction can also skip its children, stop the
+// walk or replace it outright. Members and elements removed by `fn` before
+// the walk reaches them are not visited, and removing an array element at or
+// before the one being visited skips the element that shifts into its place:
+//
+//	js.Walk(func(path simplejson.Path, node *simplejson.Json) simplejson.WalkAction {
+//		if path.Dotted() == "user.password" {
+//			return simplejson.WalkReplace("REDACTED")
+//		}
+//		return simplejson.WalkContinue
+//	})
+func (j *Json) Walk(fn func(path Path, node *Json) WalkAction) {
+	walk(Path{}, j, fn)
+}
+
+// walk reports false once the walk has been stopped
+func walk(path Path, node *Json, fn func(Path, *Json) WalkAction) bool {
+	action := fn(path, node)
+	switch action.op {
+	case walkStop:
+		return false
+	case walkSkip:
+		return true
+	case walkReplace:
+		node.unshare()
+		node.update(action.value)
+		return true
+	}
+
+	// children are read through node.lookup rather than from a snapshot, after
+	// re-reading node itself, so that the rest see any copy of a LazyClone or
+	// new array made while visiting one, and those removed are skipped
+	var keys []interface{}
+	switch v := node.data.(type) {
+	case map[string]interface{}:
+		for _, k := range node.keyOrder().keys(v) {
+			keys = append(keys, k)
+		}
+	case []interface{}:
+		for i := range v {
+			keys = append(keys, i)
+		}
+	}
+	for _, k := range keys {
+		node.refresh()
+		data, ok := node.lookup(k)
+		if !ok {
+			continue
+		}
+		child := &Json{data: data, parent: node, parentGen: node.gen, key: k}
+		if !walk(append(path[:len(path):len(path)], k), child, fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package simplejson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	js := mustJson(t, `{
+		"user": {"name": "a", "password": "secret"},
+		"tags": ["x", {"deep": true}],
+		"count": 2
+	}`)
+
+	var visited []string
+	js.Walk(func(path Path, node *Json) WalkAction {
+		visited = append(visited, path.Pointer())
+		return WalkContinue
+	})
+	expected := []string{"", "/count", "/tags", "/tags/0", "/tags/1", "/tags/1/deep",
+		"/user", "/user/name", "/user/password"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("got %#v", visited)
+	}
+
+	visited = nil
+	js.Walk(func(path Path, node *Json) WalkAction {
+		visited = append(visited, path.Dotted())
+		switch {
+		case path.Dotted() == "tags":
+			return WalkSkip
+		case path.Dotted() == "user.name":
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	if !reflect.DeepEqual(visited, []string{"", "count", "tags", "user", "user.name"}) {
+		t.Errorf("got %#v", visited)
+	}
+
+	js.Walk(func(path Path, node *Json) WalkAction {
+		switch {
+		case path.Dotted() == "user.password":
+			return WalkReplace("REDACTED")
+		case path.Pointer() == "/tags/1":
+			return WalkReplace([]interface{}{"replaced"})
+		case node.Kind() == Number:
+			node.SetPath(nil, node.MustInt()+1)
+		}
+		if path.Pointer() == "/tags/1/0" {
+			t.Errorf("visited the children of a replaced value")
+		}
+		return WalkContinue
+	})
+	if s := js.GetPath("user", "password").MustString(); s != "REDACTED" {
+		t.Errorf("got %#v", s)
+	}
+	if s := js.Get("tags").GetIndex(1).GetIndex(0).MustString(); s != "replaced" {
+		t.Errorf("got %#v", s)
+	}
+	if i := js.Get("count").MustInt(); i != 3 {
+		t.Errorf("got %#v", i)
+	}
+
+	js.Walk(func(path Path, node *Json) WalkAction {
+		return WalkReplace("root")
+	})
+	if s := js.MustString(); s != "root" {
+		t.Errorf("got %#v", s)
+	}
+}
+
+func TestWalkRemoval(t *testing.T) {
+	js := mustJson(t, `{"a": 1, "b": 2, "c": [1, 2, 3]}`)
+
+	var visited []string
+	js.Walk(func(path Path, node *Json) WalkAction {
+		visited = append(visited, path.Pointer())
+		if path.Pointer() == "/a" {
+			js.Del("b")
+			js.Get("c").RemoveIndex(2)
+		}
+		return WalkContinue
+	})
+	if !reflect.DeepEqual(visited, []string{"", "/a", "/c", "/c/0", "/c/1"}) {
+		t.Errorf("got %#v", visited)
+	}
+
+	// changes after an array is replaced reach the new one
+	js = mustJson(t, `{"a": [1, 2, 3]}`)
+	js.Walk(func(path Path, node *Json) WalkAction {
+		switch path.Pointer() {
+		case "/a/0":
+			js.Get("a").RemoveIndex(0)
+		case "/a/1":
+			return WalkReplace("X")
+		}
+		return WalkContinue
+	})
+	if b, _ := js.Encode(); string(b) != `{"a":[2,"X"]}` {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestWalkLazyClone(t *testing.T) {
+	orig := mustJson(t, `{"a": {"x": 1}, "b": {"y": 2}}`)
+	clone := orig.LazyClone()
+
+	clone.Walk(func(path Path, node *Json) WalkAction {
+		if node.Kind() == Number {
+			return WalkReplace(0)
+		}
+		return WalkContinue
+	})
+	if !clone.Equal(mustJson(t, `{"a": {"x": 0}, "b": {"y": 0}}`)) {
+		t.Errorf("got %#v", clone.Interface())
+	}
+	if !orig.Equal(mustJson(t, `{"a": {"x": 1}, "b": {"y": 2}}`)) {
+		t.Errorf("got %#v", orig.Interface())
+	}
+}
+
+func TestPath(t *testing.T) {
+	p := Path{"users", 0, "name"}
+	if s := p.String(); s != "/users/0/name" {
+		t.Errorf("got %s", s)
+	}
+	if s := p.Dotted(); s != "users[0].name" {
+		t.Errorf("got %s", s)
+	}
+
+	p = Path{0, "a/b", "app.io", "_ok", "9z", ""}
+	if s := p.Pointer(); s != "/0/a~1b/app.io/_ok/9z/" {
+		t.Errorf("got %s", s)
+	}
+	if s := p.Dotted(); s != `[0]["a/b"]["app.io"]._ok["9z"][""]` {
+		t.Errorf("got %s", s)
+	}
+
+	if s := (Path{}).Dotted(); s != "" {
+		t.Errorf("got %s", s)
+	}
+
+	js := mustJson(t, `{"users": [{"name": "a"}]}`)
+	if err := js.SetBranch(Path{"users", 0, "name"}, "b"); err != nil {
+		t.Fatalf("err %#v", err)
+	}
+	if s := js.Get("users").GetIndex(0).Get("name").MustString(); s != "b" {
+		t.Errorf("got %#v", s)
+	}
+}